@@ -0,0 +1,184 @@
+package admin
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"net"
+	"net/http"
+	"net/http/pprof"
+	"strconv"
+	"time"
+
+	"github.com/jackdes93/fcontext/sctx"
+)
+
+const (
+	defaultPort = 9090
+	defaultAddr = "127.0.0.1" // admin không có xác thực, mặc định chỉ nghe local
+)
+
+type Config struct {
+	Addr        string
+	Port        int
+	EnablePprof bool
+}
+
+// Component là HTTP server quản trị (stdlib net/http) dùng chung cho mọi service:
+//
+//	GET /healthz     liveness từ HealthChecker của các component
+//	GET /readyz      readiness
+//	GET /components  danh sách component + trạng thái lifecycle
+//	GET /config      config hiệu lực (đã che giá trị nhạy cảm)
+//	POST /reload     reload config (như SIGHUP)
+//	GET /loglevel    level hiện tại của logger
+//	PUT /loglevel    đổi level lúc chạy: {"level": "info,worker=debug"}
+//	GET /logs?n=100  các dòng log gần nhất (khi bật -log-buffer)
+//	/debug/pprof/*   khi bật admin-pprof
+type Component struct {
+	*Config
+	id  string
+	log sctx.Logger
+	sv  sctx.ServiceContext
+	srv *http.Server
+}
+
+func NewComponent(id string) *Component {
+	return &Component{
+		Config: &Config{Addr: defaultAddr, Port: defaultPort},
+		id:     id,
+	}
+}
+
+func (c *Component) ID() string { return c.id }
+// Order cao để khi khởi động tuần tự, admin thường Activate sau và dừng trước các component khác.
+// Không đảm bảo: với WithParallelLifecycle (admin không có dependency nên ở level đầu) hoặc khi
+// chuỗi dependency chứa component Order > 1000, admin có thể phục vụ trong lúc component khác
+// chưa active; /healthz và /readyz khi đó báo các component đó down.
+func (c *Component) Order() int { return 1000 }
+
+// InitFlags để trống: flag được đăng ký trên FlagSet của service qua RegisterFlags
+func (c *Component) InitFlags() {}
+
+func (c *Component) RegisterFlags(fs *flag.FlagSet) {
+	fs.StringVar(&c.Config.Addr, "admin-addr", defaultAddr, "admin http server listen address (empty: all interfaces)")
+	fs.IntVar(&c.Config.Port, "admin-port", defaultPort, "admin http server port")
+	fs.BoolVar(&c.Config.EnablePprof, "admin-pprof", false, "mount /debug/pprof on admin server")
+}
+
+func (c *Component) Activate(ctx context.Context, sv sctx.ServiceContext) error {
+	c.log = sv.Logger(c.ID())
+	c.sv = sv
+
+	ln, err := net.Listen("tcp", net.JoinHostPort(c.Config.Addr, strconv.Itoa(c.Config.Port)))
+	if err != nil {
+		return fmt.Errorf("admin listen: %w", err)
+	}
+	c.srv = &http.Server{
+		Handler:           c.Handler(),
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+
+	go func() {
+		c.log.Info("admin server listening on %s", ln.Addr())
+		if err := c.srv.Serve(ln); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			c.log.Error("admin server error: %v", err)
+		}
+	}()
+	return nil
+}
+
+func (c *Component) Stop(ctx context.Context) error {
+	if c.srv == nil {
+		return nil
+	}
+	shutdownCtx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+	err := c.srv.Shutdown(shutdownCtx)
+	c.srv = nil
+	return err
+}
+
+// Handler trả về mux của admin server, có thể mount vào server khác.
+func (c *Component) Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /healthz", func(w http.ResponseWriter, r *http.Request) {
+		writeReport(w, c.sv.Liveness(r.Context()))
+	})
+	mux.HandleFunc("GET /readyz", func(w http.ResponseWriter, r *http.Request) {
+		writeReport(w, c.sv.Readiness(r.Context()))
+	})
+	mux.HandleFunc("GET /components", func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, http.StatusOK, c.sv.Components())
+	})
+	mux.HandleFunc("GET /config", func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, http.StatusOK, c.sv.Config())
+	})
+	mux.HandleFunc("POST /reload", func(w http.ResponseWriter, r *http.Request) {
+		if err := c.sv.Reload(r.Context()); err != nil {
+			writeJSON(w, http.StatusInternalServerError, map[string]string{"error": err.Error()})
+			return
+		}
+		writeJSON(w, http.StatusOK, map[string]string{"status": "reloaded"})
+	})
+
+	mux.HandleFunc("GET /loglevel", func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, http.StatusOK, map[string]string{"level": c.sv.LogLevel()})
+	})
+	mux.HandleFunc("PUT /loglevel", func(w http.ResponseWriter, r *http.Request) {
+		var body struct {
+			Level string `json:"level"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			writeJSON(w, http.StatusBadRequest, map[string]string{"error": err.Error()})
+			return
+		}
+		if err := c.sv.SetLogLevel(body.Level); err != nil {
+			writeJSON(w, http.StatusBadRequest, map[string]string{"error": err.Error()})
+			return
+		}
+		writeJSON(w, http.StatusOK, map[string]string{"level": c.sv.LogLevel()})
+	})
+
+	mux.HandleFunc("GET /logs", func(w http.ResponseWriter, r *http.Request) {
+		n := 0
+		if v := r.URL.Query().Get("n"); v != "" {
+			var err error
+			if n, err = strconv.Atoi(v); err != nil {
+				writeJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid n: " + v})
+				return
+			}
+		}
+		logs := c.sv.RecentLogs(n)
+		if logs == nil {
+			writeJSON(w, http.StatusNotFound, map[string]string{"error": "log buffer disabled, set -log-buffer"})
+			return
+		}
+		writeJSON(w, http.StatusOK, logs)
+	})
+
+	if c.Config.EnablePprof {
+		mux.HandleFunc("/debug/pprof/", pprof.Index)
+		mux.HandleFunc("/debug/pprof/cmdline", pprof.Cmdline)
+		mux.HandleFunc("/debug/pprof/profile", pprof.Profile)
+		mux.HandleFunc("/debug/pprof/symbol", pprof.Symbol)
+		mux.HandleFunc("/debug/pprof/trace", pprof.Trace)
+	}
+	return mux
+}
+
+func writeReport(w http.ResponseWriter, r sctx.HealthReport) {
+	code := http.StatusOK
+	if !r.Healthy() {
+		code = http.StatusServiceUnavailable
+	}
+	writeJSON(w, code, r)
+}
+
+func writeJSON(w http.ResponseWriter, code int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	_ = json.NewEncoder(w).Encode(v)
+}
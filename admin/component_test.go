@@ -0,0 +1,131 @@
+package admin
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/jackdes93/fcontext/sctx"
+)
+
+type dbComponent struct {
+	healthErr error
+	uri       string
+}
+
+func (d *dbComponent) ID() string                                                { return "db" }
+func (d *dbComponent) InitFlags()                                                {}
+func (d *dbComponent) Order() int                                                { return 10 }
+func (d *dbComponent) Activate(ctx context.Context, _ sctx.ServiceContext) error { return nil }
+func (d *dbComponent) Stop(ctx context.Context) error                            { return nil }
+func (d *dbComponent) HealthCheck(ctx context.Context) error                     { return d.healthErr }
+
+func TestAdminEndpoints(t *testing.T) {
+	db := &dbComponent{}
+	adm := NewComponent("admin")
+	sv := sctx.New(
+		sctx.WithName("admin-test"),
+		sctx.WithArgs(nil),
+		sctx.WithComponent(db),
+		sctx.WithComponent(adm),
+	)
+	adm.Config.Port = 0
+
+	if err := sv.Load(); err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	defer sv.Stop()
+
+	get := func(path string) *httptest.ResponseRecorder {
+		rec := httptest.NewRecorder()
+		adm.Handler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, path, nil))
+		return rec
+	}
+
+	t.Run("healthz", func(t *testing.T) {
+		if rec := get("/healthz"); rec.Code != http.StatusOK {
+			t.Fatalf("Expected 200, got %d: %s", rec.Code, rec.Body)
+		}
+		db.healthErr = errors.New("connection refused")
+		defer func() { db.healthErr = nil }()
+
+		rec := get("/healthz")
+		if rec.Code != http.StatusServiceUnavailable {
+			t.Fatalf("Expected 503, got %d", rec.Code)
+		}
+		if !strings.Contains(rec.Body.String(), "connection refused") {
+			t.Fatalf("Expected component error in body, got %s", rec.Body)
+		}
+	})
+
+	t.Run("readyz", func(t *testing.T) {
+		if rec := get("/readyz"); rec.Code != http.StatusOK {
+			t.Fatalf("Expected 200, got %d: %s", rec.Code, rec.Body)
+		}
+	})
+
+	t.Run("components", func(t *testing.T) {
+		var comps []sctx.ComponentInfo
+		if err := json.Unmarshal(get("/components").Body.Bytes(), &comps); err != nil {
+			t.Fatal(err)
+		}
+		if len(comps) != 2 || comps[0].ID != "db" || comps[1].ID != "admin" {
+			t.Fatalf("Unexpected components %+v", comps)
+		}
+	})
+
+	t.Run("config", func(t *testing.T) {
+		var cfg []sctx.ConfigEntry
+		if err := json.Unmarshal(get("/config").Body.Bytes(), &cfg); err != nil {
+			t.Fatal(err)
+		}
+		found, local := false, false
+		for _, e := range cfg {
+			switch e.Name {
+			case "admin-port":
+				found = e.Env == "ADMIN_PORT"
+			case "admin-addr":
+				local = e.Env == "ADMIN_ADDR" && e.Value == "127.0.0.1"
+			}
+		}
+		if !found {
+			t.Fatalf("admin-port missing from config %+v", cfg)
+		}
+		if !local {
+			t.Fatalf("admin-addr should default to 127.0.0.1, got %+v", cfg)
+		}
+	})
+
+	t.Run("loglevel", func(t *testing.T) {
+		put := func(body string) *httptest.ResponseRecorder {
+			rec := httptest.NewRecorder()
+			adm.Handler().ServeHTTP(rec, httptest.NewRequest(http.MethodPut, "/loglevel", strings.NewReader(body)))
+			return rec
+		}
+		if rec := put(`{"level": "warn,db=debug"}`); rec.Code != http.StatusOK {
+			t.Fatalf("Expected 200, got %d: %s", rec.Code, rec.Body)
+		}
+		if body := get("/loglevel").Body.String(); !strings.Contains(body, `"warn,db=debug"`) {
+			t.Fatalf("Unexpected level %s", body)
+		}
+		if rec := put(`{"level": "loud"}`); rec.Code != http.StatusBadRequest {
+			t.Fatalf("Expected 400, got %d", rec.Code)
+		}
+	})
+
+	t.Run("logs disabled", func(t *testing.T) {
+		if rec := get("/logs?n=10"); rec.Code != http.StatusNotFound {
+			t.Fatalf("Expected 404, got %d", rec.Code)
+		}
+	})
+
+	t.Run("pprof disabled", func(t *testing.T) {
+		if rec := get("/debug/pprof/"); rec.Code != http.StatusNotFound {
+			t.Fatalf("Expected 404, got %d", rec.Code)
+		}
+	})
+}
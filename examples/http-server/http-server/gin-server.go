@@ -53,9 +53,11 @@ func NewGinService(id string) *ginEngineer {
 }
 
 func (g *ginEngineer) ID() string { return g.id }
-func (g *ginEngineer) InitFlags() {
-	flag.IntVar(&g.Config.Port, "gin-port", defaultPort, "gin server port.")
-	flag.StringVar(&g.Config.Mode, "gin-mode", defaultMode, "gin server mode running")
+func (g *ginEngineer) InitFlags() {}
+
+func (g *ginEngineer) RegisterFlags(fs *flag.FlagSet) {
+	fs.IntVar(&g.Config.Port, "gin-port", defaultPort, "gin server port.")
+	fs.StringVar(&g.Config.Mode, "gin-mode", defaultMode, "gin server mode running")
 }
 
 func (g *ginEngineer) WithRegistrarFactory(f RouteRegistrarFactory) *ginEngineer {
@@ -84,18 +84,24 @@ func (nj *NotificationJob) Type() string {
 type MockLogger struct{}
 
 func (ml *MockLogger) WithPrefix(prefix string) fcontext.Logger { return ml }
-func (ml *MockLogger) Info(msg string, args ...interface{})    { log.Printf("[INFO] "+msg, args...) }
-func (ml *MockLogger) Warn(msg string, args ...interface{})    { log.Printf("[WARN] "+msg, args...) }
-func (ml *MockLogger) Debug(msg string, args ...interface{})   { log.Printf("[DEBUG] "+msg, args...) }
-func (ml *MockLogger) Error(msg string, args ...interface{})   { log.Printf("[ERROR] "+msg, args...) }
+func (ml *MockLogger) Info(msg string, args ...interface{})     { log.Printf("[INFO] "+msg, args...) }
+func (ml *MockLogger) Warn(msg string, args ...interface{})     { log.Printf("[WARN] "+msg, args...) }
+func (ml *MockLogger) Debug(msg string, args ...interface{})    { log.Printf("[DEBUG] "+msg, args...) }
+func (ml *MockLogger) Error(msg string, args ...interface{})    { log.Printf("[ERROR] "+msg, args...) }
+func (ml *MockLogger) Debugw(msg string, kv ...interface{})     { log.Printf("[DEBUG] %s %v", msg, kv) }
+func (ml *MockLogger) Infow(msg string, kv ...interface{})      { log.Printf("[INFO] %s %v", msg, kv) }
+func (ml *MockLogger) Warnw(msg string, kv ...interface{})      { log.Printf("[WARN] %s %v", msg, kv) }
+func (ml *MockLogger) Errorw(msg string, kv ...interface{})     { log.Printf("[ERROR] %s %v", msg, kv) }
+func (ml *MockLogger) With(kv ...interface{}) fcontext.Logger   { return ml }
+func (ml *MockLogger) Ctx(ctx context.Context) fcontext.Logger  { return ml }
 
 // MockMetrics đơn giản để test
 type MockMetrics struct{}
 
-func (mm *MockMetrics) IncJobStarted(name string)                                   {}
-func (mm *MockMetrics) IncJobSuccess(name string, latency time.Duration)            {}
+func (mm *MockMetrics) IncJobStarted(name string)                                  {}
+func (mm *MockMetrics) IncJobSuccess(name string, latency time.Duration)           {}
 func (mm *MockMetrics) IncJobFailed(name string, err error, latency time.Duration) {}
-func (mm *MockMetrics) IncJobPermanentFailed(name string, err error)                {}
+func (mm *MockMetrics) IncJobPermanentFailed(name string, err error)               {}
 
 func main() {
 	ctx, cancel := context.WithCancel(context.Background())
@@ -104,7 +110,7 @@ func main() {
 	// Tạo pool với 4 workers
 	logger := &MockLogger{}
 	metrics := &MockMetrics{}
-	
+
 	pool := worker.NewPool(logger, metrics,
 		worker.WithName("example-hub"),
 		worker.WithSize(4),
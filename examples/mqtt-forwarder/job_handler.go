@@ -26,7 +26,7 @@ func CreateForwardMessageHandler(st *storage.StorageComponent, log fcontext.Logg
 			// Validate payload
 			var data interface{}
 			if err := json.Unmarshal(payload, &data); err != nil {
-				log.Warn("invalid json payload", "topic", topic, "error", err)
+				log.Warnw("invalid json payload", "topic", topic, "error", err)
 				// Vẫn lưu lại dù không phải JSON
 			}
 
@@ -35,7 +35,7 @@ func CreateForwardMessageHandler(st *storage.StorageComponent, log fcontext.Logg
 				return fmt.Errorf("save message failed: %w", err)
 			}
 
-			log.Debug("message forwarded", "topic", topic, "size", len(payload))
+			log.Debugw("message forwarded", "topic", topic, "size", len(payload))
 			return nil
 		}
 
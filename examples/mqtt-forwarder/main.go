@@ -18,6 +18,7 @@ func newService() fcontext.ServiceContext {
 
 	// Tạo MQTT component với topics
 	mqttComp := mqtt.NewComponent("mqtt").
+		WithDependsOn(storageComp.ID()).
 		WithTopics(map[string]byte{
 			"sensor/temperature": 1,
 			"sensor/humidity":    1,
@@ -79,7 +80,7 @@ func setupMessageForwarding(ctx context.Context, sv fcontext.ServiceContext) err
 
 		// Submit to worker pool
 		if !workerComp.Submit(j) {
-			log.Error("failed to submit job to worker pool", "topic", topic)
+			log.Errorw("failed to submit job to worker pool", "topic", topic)
 		}
 	})
 
@@ -98,7 +99,7 @@ func main() {
 
 		log := sv.Logger("main")
 		log.Info("MQTT Forwarder service started successfully")
-		log.Info("listening for MQTT messages...", "endpoints", []string{
+		log.Infow("listening for MQTT messages...", "endpoints", []string{
 			"POST   /api/v1/health",
 			"GET    /api/v1/messages/:topic",
 			"GET    /api/v1/stats/:topic",
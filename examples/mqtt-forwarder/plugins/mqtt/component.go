@@ -4,7 +4,6 @@ import (
 	"context"
 	"flag"
 	"fmt"
-	"os"
 	"sync"
 
 	mqtt "github.com/eclipse/paho.mqtt.golang"
@@ -17,16 +16,17 @@ type MQTTComponent struct {
 	client   mqtt.Client
 	cfg      Config
 	topics   map[string]byte // topic -> QoS
+	deps     []string
 	mu       sync.RWMutex
 	onMessage MessageHandler
 }
 
 type Config struct {
-	Broker   string
-	Port     int
-	Username string
-	Password string
-	ClientID string
+	Broker   string `default:"localhost" usage:"MQTT broker address"`
+	Port     int    `default:"1883" usage:"MQTT broker port"`
+	Username string `name:"user" usage:"MQTT username"`
+	Password string `name:"pass" secret:"true" usage:"MQTT password"`
+	ClientID string `default:"fcontext-mqtt-client" usage:"MQTT client ID"`
 }
 
 // MessageHandler được gọi khi nhận message từ MQTT
@@ -48,6 +48,12 @@ func (c *MQTTComponent) WithTopics(topics map[string]byte) *MQTTComponent {
 	return c
 }
 
+// WithDependsOn khai báo các component phải khởi động trước MQTT (vd: storage)
+func (c *MQTTComponent) WithDependsOn(ids ...string) *MQTTComponent {
+	c.deps = append(c.deps, ids...)
+	return c
+}
+
 func (c *MQTTComponent) WithMessageHandler(h MessageHandler) *MQTTComponent {
 	c.onMessage = h
 	return c
@@ -55,19 +61,15 @@ func (c *MQTTComponent) WithMessageHandler(h MessageHandler) *MQTTComponent {
 
 func (c *MQTTComponent) ID() string { return c.id }
 
-func (c *MQTTComponent) InitFlags() {
-	c.cfg.Broker = os.Getenv("MQTT_BROKER")
-	if c.cfg.Broker == "" {
-		c.cfg.Broker = "localhost"
-	}
-	flag.IntVar(&c.cfg.Port, "mqtt-port", 1883, "MQTT broker port")
-	flag.StringVar(&c.cfg.Broker, "mqtt-broker", c.cfg.Broker, "MQTT broker address")
-	flag.StringVar(&c.cfg.Username, "mqtt-user", "", "MQTT username")
-	flag.StringVar(&c.cfg.Password, "mqtt-pass", "", "MQTT password")
-	flag.StringVar(&c.cfg.ClientID, "mqtt-client-id", "fcontext-mqtt-client", "MQTT client ID")
+func (c *MQTTComponent) InitFlags() {}
+
+func (c *MQTTComponent) RegisterFlags(fs *flag.FlagSet) {
+	// -mqtt-broker, -mqtt-port, -mqtt-user, -mqtt-pass, -mqtt-client-id
+	fcontext.BindFlagSet(fs, &c.cfg, "mqtt")
 }
 
-func (c *MQTTComponent) Order() int { return 30 } // khởi động trước worker
+func (c *MQTTComponent) Order() int          { return 30 }
+func (c *MQTTComponent) DependsOn() []string { return c.deps }
 
 func (c *MQTTComponent) Activate(ctx context.Context, sv fcontext.ServiceContext) error {
 	c.log = sv.Logger(c.ID())
@@ -85,10 +87,7 @@ func (c *MQTTComponent) Activate(ctx context.Context, sv fcontext.ServiceContext
 
 	// Callback khi nhận message
 	opts.SetDefaultPublishHandler(func(_ mqtt.Client, msg mqtt.Message) {
-		c.log.Info("received message", map[string]interface{}{
-			"topic": msg.Topic(),
-			"size":  len(msg.Payload()),
-		})
+		c.log.Infow("received message", "topic", msg.Topic(), "size", len(msg.Payload()))
 		if c.onMessage != nil {
 			c.onMessage(msg.Topic(), msg.Payload())
 		}
@@ -103,15 +102,15 @@ func (c *MQTTComponent) Activate(ctx context.Context, sv fcontext.ServiceContext
 
 		for topic, qos := range topics {
 			if token := client.Subscribe(topic, qos, nil); token.Wait() && token.Error() != nil {
-				c.log.Error("subscribe failed", "topic", topic, "error", token.Error())
+				c.log.Errorw("subscribe failed", "topic", topic, "error", token.Error())
 			} else {
-				c.log.Info("subscribed", "topic", topic)
+				c.log.Infow("subscribed", "topic", topic)
 			}
 		}
 	})
 
 	opts.SetConnectionLostHandler(func(_ mqtt.Client, err error) {
-		c.log.Error("mqtt connection lost", "error", err)
+		c.log.Errorw("mqtt connection lost", "error", err)
 	})
 
 	c.client = mqtt.NewClient(opts)
@@ -119,7 +118,7 @@ func (c *MQTTComponent) Activate(ctx context.Context, sv fcontext.ServiceContext
 		return fmt.Errorf("mqtt connect failed: %w", token.Error())
 	}
 
-	c.log.Info("mqtt component started", "broker", fmt.Sprintf("%s:%d", c.cfg.Broker, c.cfg.Port))
+	c.log.Infow("mqtt component started", "broker", fmt.Sprintf("%s:%d", c.cfg.Broker, c.cfg.Port))
 	return nil
 }
 
@@ -130,6 +129,14 @@ func (c *MQTTComponent) Stop(ctx context.Context) error {
 	return nil
 }
 
+// HealthCheck báo lỗi khi mất kết nối tới broker
+func (c *MQTTComponent) HealthCheck(ctx context.Context) error {
+	if c.client == nil || !c.client.IsConnectionOpen() {
+		return fmt.Errorf("mqtt not connected")
+	}
+	return nil
+}
+
 // Publish method để gửi message
 func (c *MQTTComponent) Publish(topic string, payload []byte, qos byte) error {
 	if !c.client.IsConnected() {
@@ -44,14 +44,16 @@ func NewComponent(id string) *StorageComponent {
 
 func (c *StorageComponent) ID() string { return c.id }
 
-func (c *StorageComponent) InitFlags() {
-	flag.StringVar(&c.cfg.RedisAddr, "redis-addr", os.Getenv("REDIS_ADDR"), "Redis address")
-	flag.StringVar(&c.cfg.PostgresDSN, "postgres-dsn", os.Getenv("POSTGRES_DSN"), "PostgreSQL DSN")
-	flag.BoolVar(&c.cfg.EnableRedis, "enable-redis", true, "Enable Redis")
-	flag.BoolVar(&c.cfg.EnablePostgres, "enable-postgres", true, "Enable PostgreSQL")
+func (c *StorageComponent) InitFlags() {}
+
+func (c *StorageComponent) RegisterFlags(fs *flag.FlagSet) {
+	fs.StringVar(&c.cfg.RedisAddr, "redis-addr", os.Getenv("REDIS_ADDR"), "Redis address")
+	fs.StringVar(&c.cfg.PostgresDSN, "postgres-dsn", os.Getenv("POSTGRES_DSN"), "PostgreSQL DSN")
+	fs.BoolVar(&c.cfg.EnableRedis, "enable-redis", true, "Enable Redis")
+	fs.BoolVar(&c.cfg.EnablePostgres, "enable-postgres", true, "Enable PostgreSQL")
 }
 
-func (c *StorageComponent) Order() int { return 25 } // khởi động trước MQTT
+func (c *StorageComponent) Order() int { return 25 }
 
 func (c *StorageComponent) Activate(ctx context.Context, sv fcontext.ServiceContext) error {
 	c.log = sv.Logger(c.ID())
@@ -60,10 +62,10 @@ func (c *StorageComponent) Activate(ctx context.Context, sv fcontext.ServiceCont
 	if c.cfg.EnableRedis && c.cfg.RedisAddr != "" {
 		c.redis = NewRedisClient(c.cfg.RedisAddr, c.cfg.RedisTTL)
 		if err := c.redis.Ping(ctx); err != nil {
-			c.log.Error("redis ping failed", "error", err)
+			c.log.Errorw("redis ping failed", "error", err)
 			c.redis = nil
 		} else {
-			c.log.Info("redis connected", "addr", c.cfg.RedisAddr)
+			c.log.Infow("redis connected", "addr", c.cfg.RedisAddr)
 		}
 	}
 
@@ -71,11 +73,11 @@ func (c *StorageComponent) Activate(ctx context.Context, sv fcontext.ServiceCont
 	if c.cfg.EnablePostgres && c.cfg.PostgresDSN != "" {
 		pg, err := NewPostgresClient(c.cfg.PostgresDSN)
 		if err != nil {
-			c.log.Error("postgres connection failed", "error", err)
+			c.log.Errorw("postgres connection failed", "error", err)
 		} else {
 			c.postgres = pg
 			if err := c.postgres.InitSchema(ctx); err != nil {
-				c.log.Error("postgres schema init failed", "error", err)
+				c.log.Errorw("postgres schema init failed", "error", err)
 			}
 			c.log.Info("postgres connected")
 		}
@@ -103,13 +105,13 @@ func (c *StorageComponent) Stop(ctx context.Context) error {
 func (c *StorageComponent) SaveMessage(ctx context.Context, topic string, payload []byte) error {
 	if c.redis != nil {
 		if err := c.redis.SaveMessage(ctx, topic, payload); err != nil {
-			c.log.Warn("redis save failed", "error", err)
+			c.log.Warnw("redis save failed", "error", err)
 		}
 	}
 
 	if c.postgres != nil {
 		if err := c.postgres.SaveMessage(ctx, topic, payload); err != nil {
-			c.log.Warn("postgres save failed", "error", err)
+			c.log.Warnw("postgres save failed", "error", err)
 		}
 	}
 
@@ -3,6 +3,7 @@ package postgres
 
 import (
 	"context"
+	"errors"
 	"flag"
 	"time"
 
@@ -39,13 +40,15 @@ func (p *postgresDB) Order() int {
 
 func (p *postgresDB) ID() string { return p.id }
 
-func (p *postgresDB) InitFlags() {
-	flag.StringVar(&p.cfg.uri, "postgres-uri", "postgres://localhost:5432/mqtt_db?sslmode=disable", "uri connect string of postgresDB")
-	flag.IntVar(&p.cfg.maxConns, "postgres-max-conn", 4, "postgres max connections")
-	flag.IntVar(&p.cfg.minConns, "postgres-min-conn", 2, "postgres min connections")
-	flag.IntVar(&p.cfg.maxConnLifeTime, "postgres-max-life-time", 1, "postgres max connection life time")
-	flag.IntVar(&p.cfg.maxConnIdleTime, "postgres-max-conn-idle-time", 10, "postgres max connection idle time")
-	flag.IntVar(&p.cfg.healthyCheckPeriod, "postgres-healthy-check-period", 1, "postgres healthy check period")
+func (p *postgresDB) InitFlags() {}
+
+func (p *postgresDB) RegisterFlags(fs *flag.FlagSet) {
+	fs.StringVar(&p.cfg.uri, "postgres-uri", "postgres://localhost:5432/mqtt_db?sslmode=disable", "uri connect string of postgresDB")
+	fs.IntVar(&p.cfg.maxConns, "postgres-max-conn", 4, "postgres max connections")
+	fs.IntVar(&p.cfg.minConns, "postgres-min-conn", 2, "postgres min connections")
+	fs.IntVar(&p.cfg.maxConnLifeTime, "postgres-max-life-time", 1, "postgres max connection life time")
+	fs.IntVar(&p.cfg.maxConnIdleTime, "postgres-max-conn-idle-time", 10, "postgres max connection idle time")
+	fs.IntVar(&p.cfg.healthyCheckPeriod, "postgres-healthy-check-period", 1, "postgres healthy check period")
 }
 
 func (p *postgresDB) LoadConfig() (*pgxpool.Config, error) {
@@ -67,7 +70,7 @@ func (p *postgresDB) Activate(ctx context.Context, service sctx.ServiceContext)
 
 	config, err := p.LoadConfig()
 	if err != nil {
-		p.logger.Info("error load config ", err.Error())
+		p.logger.Infow("error load config", "error", err)
 		return err
 	}
 
@@ -94,6 +97,13 @@ func (p *postgresDB) Stop(ctx context.Context) error {
 	return nil
 }
 
+func (p *postgresDB) HealthCheck(ctx context.Context) error {
+	if p.client == nil {
+		return errors.New("postgresDB is not connected")
+	}
+	return p.client.Ping(ctx)
+}
+
 func (p *postgresDB) Query(ctx context.Context, query string, args ...any) ([]map[string]any, error) {
 	rows, err := p.client.Query(ctx, query)
 	if err != nil {
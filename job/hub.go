@@ -135,6 +135,7 @@ type hubJobAdapter struct {
 func (a *hubJobAdapter) Execute(ctx context.Context) error {
 	a.setState(StateRunning)
 
+	ctx = logContext(ctx, a.cfg.Name, a.RetryIndex())
 	ctx2 := ctx
 	var cancel context.CancelFunc
 	if a.cfg.MaxTimeout > 0 {
@@ -227,6 +228,8 @@ func (a *hubJobAdapter) RunWithRetry(ctx context.Context) error {
 	}
 }
 
+func (a *hubJobAdapter) Name() string { return a.cfg.Name }
+
 func (a *hubJobAdapter) State() State { 
 	a.mu.Lock()
 	defer a.mu.Unlock()
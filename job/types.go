@@ -6,6 +6,8 @@ import (
 	"math/rand"
 	"sync"
 	"time"
+
+	"github.com/jackdes93/fcontext/logctx"
 )
 
 type State int
@@ -91,6 +93,8 @@ func New(h Handler, opts ...Option) Job {
 func (j *job) Execute(ctx context.Context) error {
 	j.setState(StateRunning)
 
+	ctx = logContext(ctx, j.cfg.Name, j.RetryIndex())
+
 	// Apply timeout if configured
 	ctx2 := ctx
 	var cancel context.CancelFunc
@@ -194,6 +198,7 @@ func (j *job) RunWithRetry(ctx context.Context) error {
 	}
 }
 
+func (j *job) Name() string     { return j.cfg.Name }
 func (j *job) State() State     { j.mu.Lock(); defer j.mu.Unlock(); return j.state }
 func (j *job) RetryIndex() int  { j.mu.Lock(); defer j.mu.Unlock(); return j.retryIndex }
 func (j *job) LastError() error { j.mu.Lock(); defer j.mu.Unlock(); return j.lastErr }
@@ -206,6 +211,15 @@ func (j *job) SetRetries(rs []time.Duration) {
 
 // ---- helpers ----
 
+// logContext gắn tên job và lần chạy (1 = lần đầu) vào ctx truyền cho handler,
+// để log qua sctx.Logger.Ctx(ctx) mang theo chúng.
+func logContext(ctx context.Context, name string, retryIndex int) context.Context {
+	if name != "" {
+		ctx = logctx.With(ctx, "job", name)
+	}
+	return logctx.With(ctx, "attempt", retryIndex+2)
+}
+
 func (j *job) setState(s State) { j.mu.Lock(); j.state = s; j.mu.Unlock() }
 func (j *job) setErr(err error) { j.mu.Lock(); j.lastErr = err; j.mu.Unlock() }
 
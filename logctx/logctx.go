@@ -0,0 +1,65 @@
+// Package logctx gắn field log (request ID, job, trace ID...) vào context.Context.
+// Package không phụ thuộc gì ngoài thư viện chuẩn, để package lá (job...) gắn field
+// mà không cần import sctx; sctx.Logger.Ctx(ctx) đọc lại chúng.
+package logctx
+
+import (
+	"context"
+	"fmt"
+)
+
+// BadKey là key cho giá trị lẻ không có key đi kèm (giống log/slog)
+const BadKey = "!BADKEY"
+
+type fieldsKey struct{}
+
+// With trả về ctx mang thêm các cặp key-value; key đã có trong ctx được ghi đè.
+func With(ctx context.Context, kv ...any) context.Context {
+	if len(kv) == 0 {
+		return ctx
+	}
+	return context.WithValue(ctx, fieldsKey{}, merge(Fields(ctx), Normalize(kv)))
+}
+
+// Fields trả về các cặp key-value đã gắn bằng With (không được sửa).
+func Fields(ctx context.Context) []any {
+	if ctx == nil {
+		return nil
+	}
+	fields, _ := ctx.Value(fieldsKey{}).([]any)
+	return fields
+}
+
+// Normalize chuẩn hoá kv: key không phải string được đổi bằng fmt.Sprint,
+// phần tử cuối bị lẻ được ghi dưới BadKey thay vì làm panic.
+func Normalize(kv []any) []any {
+	out := make([]any, 0, len(kv)+1)
+	for i := 0; i < len(kv); i += 2 {
+		if i+1 == len(kv) {
+			out = append(out, BadKey, kv[i])
+			break
+		}
+		key, ok := kv[i].(string)
+		if !ok {
+			key = fmt.Sprint(kv[i])
+		}
+		out = append(out, key, kv[i+1])
+	}
+	return out
+}
+
+// merge gộp kv (đã Normalize) vào bản sao của base, key trùng lấy giá trị mới
+func merge(base, kv []any) []any {
+	out := append(make([]any, 0, len(base)+len(kv)), base...)
+next:
+	for i := 0; i+1 < len(kv); i += 2 {
+		for j := 0; j+1 < len(out); j += 2 {
+			if out[j] == kv[i] {
+				out[j+1] = kv[i+1]
+				continue next
+			}
+		}
+		out = append(out, kv[i], kv[i+1])
+	}
+	return out
+}
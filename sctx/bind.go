@@ -0,0 +1,317 @@
+package sctx
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+)
+
+var ErrRequiredFlag = errors.New("sctx: required flag not set")
+
+var durationType = reflect.TypeOf(time.Duration(0))
+
+// Bind đăng ký flag cho mọi field exported của struct cfg (truyền con trỏ), gọi trong InitFlags.
+// Tag hỗ trợ:
+//
+//	name:"max-conns"    tên flag (mặc định: tên field dạng kebab-case), nối sau prefix bằng "-"
+//	env:"PG_MAX_CONNS"  tên biến môi trường thay cho tên suy ra từ flag
+//	default:"4"         giá trị mặc định
+//	usage:"..."         mô tả flag
+//	required:"true"     bắt buộc phải được set từ file, env hoặc command line
+//	secret:"true"       giá trị bị che khi in config
+//	oneof:"dev stg prd" min:"1" max:"64" pattern:"^[a-z]+$" url:"true" file:"true"
+//	                    constraint, xem Constrain
+//
+// Struct lồng nhau dùng tên field làm prefix con; name:"-" bỏ qua field.
+// Kiểu hỗ trợ: string, bool, int*, uint*, float*, time.Duration, slice ("a,b") và map ("k=v,k2=v2").
+// Lỗi được trả về từ Load.
+func Bind(cfg any, prefix string) {
+	BindFlagSet(flag.CommandLine, cfg, prefix)
+}
+
+// BindFlagSet giống Bind nhưng đăng ký trên fs.
+func BindFlagSet(fs *flag.FlagSet, cfg any, prefix string) {
+	rv := reflect.ValueOf(cfg)
+	if rv.Kind() != reflect.Pointer || rv.IsNil() || rv.Elem().Kind() != reflect.Struct {
+		addBindErr(fs, fmt.Errorf("sctx: bind %q: want pointer to struct, got %T", prefix, cfg))
+		return
+	}
+	bindStruct(fs, rv.Elem(), prefix)
+}
+
+func bindStruct(fs *flag.FlagSet, sv reflect.Value, prefix string) {
+	st := sv.Type()
+	for i := 0; i < st.NumField(); i++ {
+		sf := st.Field(i)
+		if !sf.IsExported() {
+			continue
+		}
+		name := sf.Tag.Get("name")
+		if name == "-" {
+			continue
+		}
+		if name == "" {
+			name = kebabCase(sf.Name)
+		}
+		if prefix != "" {
+			name = prefix + "-" + name
+		}
+
+		fv := sv.Field(i)
+		if sf.Type.Kind() == reflect.Struct && sf.Type != durationType {
+			bindStruct(fs, fv, name)
+			continue
+		}
+		if !bindable(sf.Type) {
+			addBindErr(fs, fmt.Errorf("sctx: bind %s: unsupported type %s", name, sf.Type))
+			continue
+		}
+		// flag.Var panic khi trùng tên, báo lỗi qua Load thay vào đó
+		if fs.Lookup(name) != nil {
+			addBindErr(fs, fmt.Errorf("sctx: bind %s: flag already defined (field %s)", name, sf.Name))
+			continue
+		}
+
+		bv := &boundValue{
+			v:        fv,
+			env:      sf.Tag.Get("env"),
+			required: sf.Tag.Get("required") == "true",
+			secret:   sf.Tag.Get("secret") == "true",
+		}
+		if def, ok := sf.Tag.Lookup("default"); ok {
+			if err := bv.assign(def); err != nil {
+				addBindErr(fs, fmt.Errorf("sctx: bind %s: default %q: %w", name, def, err))
+			}
+		}
+		fs.Var(bv, name, sf.Tag.Get("usage"))
+
+		cs, err := tagConstraints(sf.Tag)
+		if err != nil {
+			addBindErr(fs, fmt.Errorf("sctx: bind %s: %w", name, err))
+			continue
+		}
+		if len(cs) > 0 {
+			ConstrainFlagSet(fs, name, cs...)
+		}
+	}
+}
+
+// addBindErr ghi lỗi Bind (field không hỗ trợ, default sai...) vào AppFlagSet đang đăng ký trên fs,
+// để Load trả về thay vì panic trong InitFlags.
+func addBindErr(fs *flag.FlagSet, err error) {
+	if a := appFlagSetOf(fs); a != nil {
+		a.bindErrs = append(a.bindErrs, err)
+	}
+}
+
+// validateBindings gom lỗi Bind, lỗi parse giá trị và flag required còn thiếu.
+func (s *serviceCtx) validateBindings() error {
+	errs := append([]error(nil), s.cmdLine.bindErrs...)
+
+	s.cmdLine.VisitAll(func(f *flag.Flag) {
+		bv, ok := f.Value.(*boundValue)
+		if !ok {
+			return
+		}
+		switch {
+		case bv.err != nil:
+			errs = append(errs, fmt.Errorf("sctx: flag %s: %w", f.Name, bv.err))
+		case bv.required && !bv.set:
+			errs = append(errs, fmt.Errorf("%w: -%s ($%s)", ErrRequiredFlag, f.Name, s.cmdLine.envNameFor(f.Name)))
+		}
+	})
+	return errors.Join(errs...)
+}
+
+// boundValue là flag.Value ghi thẳng vào field của struct đã Bind.
+type boundValue struct {
+	v        reflect.Value
+	env      string
+	required bool
+	secret   bool
+	set      bool
+	err      error // lỗi parse gần nhất, báo lại khi Load
+}
+
+func (b *boundValue) String() string {
+	if b == nil || !b.v.IsValid() {
+		return ""
+	}
+	return formatValue(b.v)
+}
+
+func (b *boundValue) Set(s string) error {
+	if err := b.assign(s); err != nil {
+		b.err = err
+		return err
+	}
+	b.set, b.err = true, nil
+	return nil
+}
+
+func (b *boundValue) IsBoolFlag() bool {
+	return b != nil && b.v.IsValid() && b.v.Kind() == reflect.Bool
+}
+
+// EnvName trả về tên env khai báo qua tag env (rỗng nếu không có).
+func (b *boundValue) EnvName() string { return b.env }
+
+func (b *boundValue) IsSecret() bool { return b.secret }
+
+// assign parse s vào field; slice và map được thay toàn bộ (không append).
+func (b *boundValue) assign(s string) error {
+	switch b.v.Kind() {
+	case reflect.Slice:
+		out := reflect.MakeSlice(b.v.Type(), 0, 0)
+		for _, part := range splitList(s) {
+			ev := reflect.New(b.v.Type().Elem()).Elem()
+			if err := parseScalar(ev, part); err != nil {
+				return err
+			}
+			out = reflect.Append(out, ev)
+		}
+		b.v.Set(out)
+	case reflect.Map:
+		out := reflect.MakeMap(b.v.Type())
+		for _, part := range splitList(s) {
+			k, v, ok := strings.Cut(part, "=")
+			if !ok {
+				return fmt.Errorf("invalid map entry %q, want key=value", part)
+			}
+			kv := reflect.New(b.v.Type().Key()).Elem()
+			if err := parseScalar(kv, strings.TrimSpace(k)); err != nil {
+				return err
+			}
+			vv := reflect.New(b.v.Type().Elem()).Elem()
+			if err := parseScalar(vv, strings.TrimSpace(v)); err != nil {
+				return err
+			}
+			out.SetMapIndex(kv, vv)
+		}
+		b.v.Set(out)
+	default:
+		return parseScalar(b.v, s)
+	}
+	return nil
+}
+
+func splitList(s string) []string {
+	if strings.TrimSpace(s) == "" {
+		return nil
+	}
+	parts := strings.Split(s, ",")
+	for i := range parts {
+		parts[i] = strings.TrimSpace(parts[i])
+	}
+	return parts
+}
+
+func parseScalar(v reflect.Value, s string) error {
+	if v.Type() == durationType {
+		d, err := time.ParseDuration(s)
+		if err != nil {
+			return err
+		}
+		v.SetInt(int64(d))
+		return nil
+	}
+	switch v.Kind() {
+	case reflect.String:
+		v.SetString(s)
+	case reflect.Bool:
+		x, err := strconv.ParseBool(s)
+		if err != nil {
+			return err
+		}
+		v.SetBool(x)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		x, err := strconv.ParseInt(s, 0, v.Type().Bits())
+		if err != nil {
+			return err
+		}
+		v.SetInt(x)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		x, err := strconv.ParseUint(s, 0, v.Type().Bits())
+		if err != nil {
+			return err
+		}
+		v.SetUint(x)
+	case reflect.Float32, reflect.Float64:
+		x, err := strconv.ParseFloat(s, v.Type().Bits())
+		if err != nil {
+			return err
+		}
+		v.SetFloat(x)
+	default:
+		return fmt.Errorf("unsupported type %s", v.Type())
+	}
+	return nil
+}
+
+func formatValue(v reflect.Value) string {
+	if v.Type() == durationType {
+		return time.Duration(v.Int()).String()
+	}
+	switch v.Kind() {
+	case reflect.Slice:
+		parts := make([]string, v.Len())
+		for i := range parts {
+			parts[i] = formatValue(v.Index(i))
+		}
+		return strings.Join(parts, ",")
+	case reflect.Map:
+		parts := make([]string, 0, v.Len())
+		iter := v.MapRange()
+		for iter.Next() {
+			parts = append(parts, formatValue(iter.Key())+"="+formatValue(iter.Value()))
+		}
+		sort.Strings(parts)
+		return strings.Join(parts, ",")
+	default:
+		return fmt.Sprint(v.Interface())
+	}
+}
+
+func bindable(t reflect.Type) bool {
+	switch t.Kind() {
+	case reflect.Slice:
+		return scalarKind(t.Elem())
+	case reflect.Map:
+		return scalarKind(t.Key()) && scalarKind(t.Elem())
+	default:
+		return scalarKind(t)
+	}
+}
+
+func scalarKind(t reflect.Type) bool {
+	switch t.Kind() {
+	case reflect.String, reflect.Bool,
+		reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		return true
+	}
+	return false
+}
+
+// kebabCase: MaxConns -> max-conns, ClientID -> client-id
+func kebabCase(s string) string {
+	rs := []rune(s)
+	var b strings.Builder
+	for i, r := range rs {
+		if unicode.IsUpper(r) {
+			if i > 0 && (unicode.IsLower(rs[i-1]) || (i+1 < len(rs) && unicode.IsLower(rs[i+1]))) {
+				b.WriteByte('-')
+			}
+			r = unicode.ToLower(r)
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
@@ -0,0 +1,198 @@
+package sctx
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+)
+
+var ErrUnknownCommand = errors.New("sctx: unknown command")
+
+// stdout cho phép test bắt output của các command dựng sẵn
+var stdout io.Writer = os.Stdout
+
+// CommandFunc chạy một subcommand với các arg còn lại sau tên command.
+// ctx bị huỷ khi nhận SIGINT/SIGTERM; component nào đã Load sẽ được Stop sau khi command trả về.
+type CommandFunc func(ctx context.Context, app ServiceContext, args []string) error
+
+type command struct {
+	name  string
+	usage string
+	fn    CommandFunc
+}
+
+// WithCommand đăng ký subcommand cho Execute (vd: "migrate"). Tên nhiều từ như "db seed" được hỗ trợ.
+func WithCommand(name, usage string, fn CommandFunc) RunOption {
+	return func(c *runConfig) {
+		c.commands = append(c.commands, command{name: name, usage: usage, fn: fn})
+	}
+}
+
+// Execute chọn subcommand từ các arg còn lại sau flag (app.Args()) rồi chạy nó:
+//
+//	serve         RunApps với các app đã khai báo (mặc định khi không có command)
+//	env           in tài liệu biến môi trường (-format dotenv|markdown|json-schema)
+//	config print  in config hiệu lực kèm nguồn (-format json|table), đã che giá trị nhạy cảm
+//	healthcheck   Load, kiểm tra Readiness rồi Stop; lỗi nếu có component down
+//	help          in danh sách command
+//
+// Command do người dùng khai báo bằng WithCommand được ưu tiên hơn command dựng sẵn cùng tên.
+func Execute(app ServiceContext, opts ...RunOption) error {
+	cfg := &runConfig{grace: DefaultGracePeriod}
+	for _, o := range opts {
+		o(cfg)
+	}
+	cmds := cfg.allCommands()
+	defer closeLogSinks(app)
+
+	// flag sai thì dừng luôn; -h đã in usage của flag, in thêm danh sách command
+	if p, ok := app.(interface{ parseErr() error }); ok {
+		if err := p.parseErr(); errors.Is(err, flag.ErrHelp) {
+			printCommands(cmds)
+			return nil
+		} else if err != nil {
+			return err
+		}
+	}
+
+	args := app.Args()
+	if len(args) == 0 {
+		args = []string{"serve"}
+	}
+	cmd, rest, ok := matchCommand(cmds, args)
+	if !ok {
+		printCommands(cmds)
+		return fmt.Errorf("%w: %q", ErrUnknownCommand, strings.Join(args, " "))
+	}
+
+	if cmd.name == "serve" && cmd.fn == nil {
+		if len(rest) > 0 {
+			return fmt.Errorf("sctx: serve: unexpected arguments %q", rest)
+		}
+		rep, err := runApps(app, cfg)
+		if err != nil {
+			return err
+		}
+		return rep.Err()
+	}
+
+	ctx, cancel := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer cancel()
+	err := cmd.fn(ctx, app, rest)
+	if stopErr := app.StopContext(context.WithoutCancel(ctx)); stopErr != nil {
+		err = errors.Join(err, stopErr)
+	}
+	return err
+}
+
+func (c *runConfig) allCommands() []command {
+	return append(append([]command(nil), c.commands...), builtinCommands(c)...)
+}
+
+func builtinCommands(cfg *runConfig) []command {
+	noArgs := func(name string, fn CommandFunc) CommandFunc {
+		return func(ctx context.Context, app ServiceContext, args []string) error {
+			if len(args) > 0 {
+				return fmt.Errorf("sctx: %s: unexpected arguments %q", name, args)
+			}
+			return fn(ctx, app, args)
+		}
+	}
+	return []command{
+		{name: "serve", usage: "Start the service (default)"},
+		{name: "env", usage: "Print env docs: -format dotenv|markdown|json-schema", fn: runEnv},
+		{name: "config print", usage: "Print effective config with sources: -format json|table", fn: runConfigPrint},
+		{name: "healthcheck", usage: "Load components, check readiness and exit", fn: noArgs("healthcheck", runHealthcheck)},
+		{name: "help", usage: "Show commands", fn: func(context.Context, ServiceContext, []string) error {
+			printCommands(cfg.allCommands())
+			return nil
+		}},
+	}
+}
+
+func runEnv(_ context.Context, app ServiceContext, args []string) error {
+	fs := flag.NewFlagSet("env", flag.ContinueOnError)
+	format := fs.String("format", string(EnvFormatDotenv), "dotenv | markdown | json-schema")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	return app.WriteEnv(stdout, EnvFormat(*format))
+}
+
+func runConfigPrint(_ context.Context, app ServiceContext, args []string) error {
+	fs := flag.NewFlagSet("config print", flag.ContinueOnError)
+	format := fs.String("format", "json", "json | table")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	switch *format {
+	case "json":
+		return writeIndentJSON(stdout, app.Config())
+	case "table":
+		return writeConfigTable(stdout, app.Config())
+	default:
+		return fmt.Errorf("sctx: config print: unknown format %q (want json or table)", *format)
+	}
+}
+
+func runHealthcheck(ctx context.Context, app ServiceContext, _ []string) error {
+	if err := app.LoadContext(ctx); err != nil {
+		return err
+	}
+	report := app.Readiness(ctx)
+	if err := writeIndentJSON(stdout, report); err != nil {
+		return err
+	}
+	if !report.Healthy() {
+		return fmt.Errorf("sctx: healthcheck: service is %s", report.Status)
+	}
+	return nil
+}
+
+// matchCommand chọn command có tên (nhiều từ) khớp dài nhất với đầu args.
+func matchCommand(cmds []command, args []string) (command, []string, bool) {
+	var best command
+	bestLen := 0
+	for _, c := range cmds {
+		words := strings.Fields(c.name)
+		if len(words) <= bestLen || len(words) > len(args) {
+			continue
+		}
+		match := true
+		for i, w := range words {
+			if args[i] != w {
+				match = false
+				break
+			}
+		}
+		if match {
+			best, bestLen = c, len(words)
+		}
+	}
+	return best, args[bestLen:], bestLen > 0
+}
+
+func printCommands(cmds []command) {
+	_, _ = fmt.Fprintln(os.Stderr, "Commands:")
+	seen := make(map[string]bool)
+	for _, c := range cmds {
+		if seen[c.name] {
+			continue
+		}
+		seen[c.name] = true
+		_, _ = fmt.Fprintf(os.Stderr, "  %-14s %s\n", c.name, c.usage)
+	}
+}
+
+func writeIndentJSON(w io.Writer, v any) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(v)
+}
@@ -1,6 +1,9 @@
 package sctx
 
-import "context"
+import (
+	"context"
+	"flag"
+)
 
 type Component interface {
 	ID() string
@@ -10,6 +13,14 @@ type Component interface {
 	Order() int
 }
 
+// FlagRegistrar là interface tuỳ chọn: component đăng ký flag trên FlagSet riêng của ServiceContext
+// thay vì InitFlags. Component chỉ có InitFlags vẫn chạy được nhưng flag của nó nằm trên
+// flag.CommandLine toàn cục (sctx chép sang set của service), nên không dùng được cho hai service
+// trong một process.
+type FlagRegistrar interface {
+	RegisterFlags(fs *flag.FlagSet)
+}
+
 func componentOrder(c Component) int {
 	type orderer interface{ Order() int }
 	if o, ok := any(c).(orderer); ok {
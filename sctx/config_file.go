@@ -0,0 +1,133 @@
+package sctx
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/BurntSushi/toml"
+	"gopkg.in/yaml.v3"
+)
+
+// readConfigFile đọc file config (YAML/JSON/TOML theo phần mở rộng) và trả về
+// map tên flag -> giá trị. Key lồng nhau được nối bằng "." rồi so khớp với tên flag
+// theo cùng quy tắc chuẩn hoá của envNameFor (vd: postgres: {max-conns: 5} -> -postgres.max-conns).
+// Key không khớp flag nào được trả về trong unknown.
+func readConfigFile(fs *AppFlagSet, path string) (values map[string]string, unknown []string, err error) {
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	tree := map[string]any{}
+	switch ext := strings.ToLower(filepath.Ext(path)); ext {
+	case ".yaml", ".yml":
+		err = yaml.Unmarshal(raw, &tree)
+	case ".json":
+		err = json.Unmarshal(raw, &tree)
+	case ".toml":
+		err = toml.Unmarshal(raw, &tree)
+	default:
+		return nil, nil, fmt.Errorf("sctx: config file %s: unsupported format %q (want .yaml, .yml, .json or .toml)", path, ext)
+	}
+	if err != nil {
+		return nil, nil, fmt.Errorf("sctx: config file %s: %w", path, err)
+	}
+
+	byKey := make(map[string]string)
+	fs.VisitAll(func(f *flag.Flag) { byKey[configKey(f.Name)] = f.Name })
+
+	values = make(map[string]string)
+	var walk func(prefix string, m map[string]any)
+	walk = func(prefix string, m map[string]any) {
+		for k, v := range m {
+			path := k
+			if prefix != "" {
+				path = prefix + "." + k
+			}
+			if name, ok := byKey[configKey(path)]; ok {
+				values[name] = configString(v)
+				continue
+			}
+			if sub, ok := v.(map[string]any); ok {
+				walk(path, sub)
+				continue
+			}
+			unknown = append(unknown, path)
+		}
+	}
+	walk("", tree)
+	sort.Strings(unknown)
+	return values, unknown, nil
+}
+
+// configKey chuẩn hoá key giống envNameFor nhưng không kèm prefix
+func configKey(name string) string {
+	return strings.ToUpper(strings.NewReplacer(".", "_", "-", "_").Replace(name))
+}
+
+// configString đổi giá trị đã decode sang dạng chuỗi mà flag.Value.Set hiểu:
+// list -> "a,b", map -> "k=v,k2=v2".
+func configString(v any) string {
+	switch x := v.(type) {
+	case nil:
+		return ""
+	case string:
+		return x
+	case float64:
+		return strconv.FormatFloat(x, 'f', -1, 64)
+	case time.Time:
+		return x.Format(time.RFC3339)
+	case []any:
+		parts := make([]string, len(x))
+		for i, e := range x {
+			parts[i] = configString(e)
+		}
+		return strings.Join(parts, ",")
+	case map[string]any:
+		keys := make([]string, 0, len(x))
+		for k := range x {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		parts := make([]string, len(keys))
+		for i, k := range keys {
+			parts[i] = k + "=" + configString(x[k])
+		}
+		return strings.Join(parts, ",")
+	default:
+		return fmt.Sprint(x)
+	}
+}
+
+// loadConfigFile nạp file config (flag -config-file hoặc CONFIG_FILE) vào flag.
+// Gọi trước khi áp ENV để thứ tự ưu tiên là: default < file < .env < env < command line.
+func (s *serviceCtx) loadConfigFile() error {
+	path := s.configFile
+	if path == "" {
+		path = os.Getenv(s.cmdLine.envNameFor("config-file"))
+	}
+	if path == "" {
+		return nil
+	}
+
+	values, unknown, err := readConfigFile(s.cmdLine, path)
+	if err != nil {
+		return err
+	}
+	for name, v := range values {
+		if err := setFlagValue(s.cmdLine.FlagSet, s.cmdLine.Lookup(name), v); err != nil {
+			return fmt.Errorf("sctx: config file %s: flag %s: %w", path, name, err)
+		}
+	}
+	s.configFilePath = path
+	s.fileConfig = values
+	s.configUnknown = unknown
+	return nil
+}
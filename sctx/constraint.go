@@ -0,0 +1,202 @@
+package sctx
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"net/url"
+	"os"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Constraint kiểm tra giá trị cuối cùng (sau mọi tầng config) của một flag.
+// Trừ Required, các constraint bỏ qua giá trị rỗng để flag tuỳ chọn vẫn hợp lệ.
+type Constraint func(value string) error
+
+// Validator là interface tuỳ chọn: component tự kiểm tra config của nó (vd: min <= max).
+// Validate chạy sau khi parse flag và trước khi bất kỳ component nào được Activate.
+type Validator interface {
+	Validate() error
+}
+
+// Constrain gắn constraint cho flag name, gọi trong InitFlags sau khi khai báo flag.
+func Constrain(name string, cs ...Constraint) {
+	ConstrainFlagSet(flag.CommandLine, name, cs...)
+}
+
+// ConstrainFlagSet giống Constrain nhưng cho flag trên fs (dùng trong RegisterFlags).
+// Chỉ có hiệu lực với FlagSet sctx truyền vào RegisterFlags/InitFlags; constraint được gộp
+// vào service (kèm đổi tên theo namespace).
+func ConstrainFlagSet(fs *flag.FlagSet, name string, cs ...Constraint) {
+	if a := appFlagSetOf(fs); a != nil {
+		a.Constrain(name, cs...)
+	}
+}
+
+func Required() Constraint {
+	return func(v string) error {
+		if strings.TrimSpace(v) == "" {
+			return errors.New("is required")
+		}
+		return nil
+	}
+}
+
+func OneOf(values ...string) Constraint {
+	return func(v string) error {
+		if v == "" {
+			return nil
+		}
+		for _, x := range values {
+			if v == x {
+				return nil
+			}
+		}
+		return fmt.Errorf("must be one of %s (got %q)", strings.Join(values, ", "), v)
+	}
+}
+
+func Min(n float64) Constraint {
+	return func(v string) error {
+		x, err := parseNumber(v)
+		if err != nil || v == "" {
+			return err
+		}
+		if x < n {
+			return fmt.Errorf("must be >= %v (got %s)", n, v)
+		}
+		return nil
+	}
+}
+
+func Max(n float64) Constraint {
+	return func(v string) error {
+		x, err := parseNumber(v)
+		if err != nil || v == "" {
+			return err
+		}
+		if x > n {
+			return fmt.Errorf("must be <= %v (got %s)", n, v)
+		}
+		return nil
+	}
+}
+
+// Matches kiểm tra giá trị theo regexp; pattern sai được báo như một vi phạm.
+func Matches(pattern string) Constraint {
+	re, err := regexp.Compile(pattern)
+	return func(v string) error {
+		if err != nil {
+			return fmt.Errorf("invalid pattern %q: %w", pattern, err)
+		}
+		if v != "" && !re.MatchString(v) {
+			return fmt.Errorf("must match %s (got %q)", pattern, v)
+		}
+		return nil
+	}
+}
+
+// IsURL yêu cầu URL tuyệt đối có scheme và host (vd: postgres://db:5432/app).
+func IsURL() Constraint {
+	return func(v string) error {
+		if v == "" {
+			return nil
+		}
+		u, err := url.Parse(v)
+		if err != nil || u.Scheme == "" || u.Host == "" {
+			return fmt.Errorf("must be an absolute URL (got %q)", redactValue("", v))
+		}
+		return nil
+	}
+}
+
+func FileExists() Constraint {
+	return func(v string) error {
+		if v == "" {
+			return nil
+		}
+		st, err := os.Stat(v)
+		if err != nil {
+			return fmt.Errorf("file %s: %w", v, errors.Unwrap(err))
+		}
+		if st.IsDir() {
+			return fmt.Errorf("%s is a directory, want a file", v)
+		}
+		return nil
+	}
+}
+
+func parseNumber(v string) (float64, error) {
+	if v == "" {
+		return 0, nil
+	}
+	x, err := strconv.ParseFloat(v, 64)
+	if err != nil {
+		return 0, fmt.Errorf("must be a number (got %q)", v)
+	}
+	return x, nil
+}
+
+// tagConstraints dịch tag của Bind (oneof, min, max, pattern, url, file) sang Constraint.
+func tagConstraints(tag reflect.StructTag) ([]Constraint, error) {
+	var cs []Constraint
+	if v := tag.Get("oneof"); v != "" {
+		cs = append(cs, OneOf(strings.Fields(v)...))
+	}
+	for _, b := range []struct {
+		key string
+		fn  func(float64) Constraint
+	}{{"min", Min}, {"max", Max}} {
+		if v := tag.Get(b.key); v != "" {
+			n, err := strconv.ParseFloat(v, 64)
+			if err != nil {
+				return nil, fmt.Errorf("tag %s:%q: %w", b.key, v, err)
+			}
+			cs = append(cs, b.fn(n))
+		}
+	}
+	if v := tag.Get("pattern"); v != "" {
+		if _, err := regexp.Compile(v); err != nil {
+			return nil, fmt.Errorf("tag pattern:%q: %w", v, err)
+		}
+		cs = append(cs, Matches(v))
+	}
+	if tag.Get("url") == "true" {
+		cs = append(cs, IsURL())
+	}
+	if tag.Get("file") == "true" {
+		cs = append(cs, FileExists())
+	}
+	return cs, nil
+}
+
+// validate chạy mọi kiểm tra trước khi Activate và gom tất cả vi phạm vào một lỗi:
+// lỗi đăng ký/parse flag, Bind, constraint của flag, rồi Validate() của từng component.
+func (s *serviceCtx) validate() error {
+	errs := []error{s.initErr, s.validateBindings()}
+	s.cmdLine.VisitAll(func(f *flag.Flag) {
+		v := f.Value.String()
+		for _, c := range s.cmdLine.constraints[f.Name] {
+			err := c(v)
+			if err == nil {
+				continue
+			}
+			msg := err.Error()
+			if shown := s.cmdLine.redact(f, v); shown != v {
+				msg = strings.ReplaceAll(msg, v, shown) // không để lộ secret trong thông báo lỗi
+			}
+			errs = append(errs, fmt.Errorf("sctx: flag -%s ($%s) %s", f.Name, s.cmdLine.envNameFor(f.Name), msg))
+		}
+	})
+	for _, c := range s.components {
+		if v, ok := c.(Validator); ok {
+			if err := v.Validate(); err != nil {
+				errs = append(errs, fmt.Errorf("sctx: %s: %w", c.ID(), err))
+			}
+		}
+	}
+	return errors.Join(errs...)
+}
@@ -2,13 +2,16 @@ package sctx
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
 	"flag"
 	"fmt"
+	"io"
 	"os"
-	"sort"
-
-	"github.com/joho/godotenv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
 )
 
 const (
@@ -19,13 +22,28 @@ const (
 
 type ServiceContext interface {
 	Load() error
+	LoadContext(ctx context.Context) error
 	MustGet(id string) any
 	Get(id string) (any, bool)
 	Logger(prefix string) Logger
 	EnvName() string
 	GetName() string
 	Stop() error
+	StopContext(ctx context.Context) error
+	Reload(ctx context.Context) error
 	OutEnv()
+	WriteEnv(w io.Writer, format EnvFormat) error
+	Liveness(ctx context.Context) HealthReport
+	Readiness(ctx context.Context) HealthReport
+	Done() <-chan struct{}
+	Err() error
+	Components() []ComponentInfo
+	States() map[string]ComponentState
+	Config() []ConfigEntry
+	Args() []string
+	SetLogLevel(spec string) error
+	LogLevel() string
+	RecentLogs(n int) []json.RawMessage
 }
 
 type serviceCtx struct {
@@ -33,58 +51,233 @@ type serviceCtx struct {
 	env        string
 	envFile    string
 	components []Component
+	order      []Component
+	levels     [][]Component
+	parallel   bool
+	loaded     atomic.Bool
+	lifeMu     sync.Mutex
+	reloadMu   sync.Mutex
+	stateMu    sync.RWMutex
+	states     map[string]ComponentState
 	store      map[string]Component
 	cmdLine    *AppFlagSet
+	envPrefix  string
+	namespaces bool
 	logger     Logger
+	logLevel   string
+	logLevels  *logLevels
+	logSinks   logSinkConfig
+	logFile    *rotatingFile
+	logRing    *ringBuffer
+	args       []string
+	initErr    error
+
+	activateTimeout time.Duration
+	stopTimeout     time.Duration
+	timeouts        map[string]componentTimeouts
+
+	runCtx       context.Context
+	runners      map[string]*runner
+	restartSpecs map[string]RestartSpec
+	fatal        chan struct{}
+	fatalOnce    sync.Once
+	fatalErr     error
+
+	flagOwners  map[string]string
+	flagLocal   map[string]string       // tên flag có namespace -> tên component đã đăng ký
+	constraints map[string][]Constraint // từ WithFlagConstraints, gộp vào cmdLine trong initFlags
+	envFiles    []string
+	fileEnv     map[string]string
+
+	configFile     string
+	configFilePath string
+	fileConfig     map[string]string
+	configUnknown  []string
+
+	secretSources []SecretSource
+	sourceSecrets map[string]string
+	sources       map[string]ConfigSource
+	flagValues    map[string]string // giá trị hiện hành của flag (sau parse và các lần Reload)
+	cfgMu         sync.RWMutex      // bảo vệ sources, flagValues và flag của service lúc chạy
+	logConfigOn   bool
 }
 
 func New(opts ...Option) ServiceContext {
 	sv := &serviceCtx{
-		store: make(map[string]Component),
+		store:    make(map[string]Component),
+		states:   make(map[string]ComponentState),
+		timeouts: make(map[string]componentTimeouts),
+
+		runCtx:       context.Background(),
+		runners:      make(map[string]*runner),
+		restartSpecs: make(map[string]RestartSpec),
+		fatal:        make(chan struct{}),
+
+		flagOwners:  make(map[string]string),
+		flagLocal:   make(map[string]string),
+		constraints: make(map[string][]Constraint),
+		flagValues:  make(map[string]string),
+		args:        os.Args[1:],
 	}
 
 	for _, opt := range opts {
 		opt(sv)
 	}
-	sv.initFlags()
-	sv.cmdLine = NewFlagSet(sv.name, nil, "")
-	if err := sv.parseFlags(); err != nil {
-		panic(err)
+	sv.cmdLine = NewFlagSet(sv.name, flag.NewFlagSet(sv.name, flag.ContinueOnError), sv.envPrefix)
+	// lỗi đăng ký/parse (flag trùng, flag sai, env file không tồn tại...) được trả về từ Load
+	sv.initErr = sv.initFlags()
+	if sv.initErr == nil {
+		sv.initErr = sv.parseFlags()
 	}
 
+	// level sai được báo từ Load (constraint của -log-level), tạm thời dùng info
+	sv.logLevels, _ = newLogLevels(sv.logLevel)
 	if sv.logger == nil {
-		sv.logger = newZeroLogger(sv.name, sv.env)
+		file, ring, err := sv.openLogSinks()
+		sv.initErr = errors.Join(sv.initErr, err)
+		var sinks []io.Writer
+		if file != nil {
+			sv.logFile, sinks = file, append(sinks, file)
+		}
+		if ring != nil {
+			sv.logRing, sinks = ring, append(sinks, ring)
+		}
+		sv.logger = newZeroLogger(sv.name, sv.env, sv.logLevels, sv.logSinks.sample, sinks...)
+	}
+	if len(sv.envFiles) > 0 {
+		sv.logger.Info("Loaded env files: %s", strings.Join(sv.envFiles, ", "))
+	}
+	if len(sv.configUnknown) > 0 {
+		sv.logger.Warn("Config file %s has keys that match no flag: %s",
+			sv.configFilePath, strings.Join(sv.configUnknown, ", "))
 	}
 	return sv
 }
 
-func (s *serviceCtx) initFlags() {
-	flag.StringVar(&s.env, "app-env", DevEnv, "Env for service. Ex: dev | stg | prd")
-	flag.StringVar(&s.envFile, "env-file", "", "Path to .env file")
+func (s *serviceCtx) initFlags() error {
+	fs := s.cmdLine.FlagSet
+	fs.StringVar(&s.env, "app-env", DevEnv, "Env for service. Ex: dev | stg | prd")
+	fs.StringVar(&s.envFile, "env-file", "", "Path to .env file(s), comma separated; replaces .env, .env.<app-env>, .env.local")
+	fs.StringVar(&s.configFile, "config-file", "", "Path to config file (.yaml, .yml, .json, .toml)")
+	fs.StringVar(&s.logLevel, "log-level", "info", "Log level, with optional per-prefix overrides. Ex: info,worker=debug,mqtt=warn")
+	s.logSinks.registerFlags(fs)
+	s.cmdLine.Constrain("app-env", OneOf(DevEnv, StgEnv, PrdEnv))
+	s.cmdLine.Constrain("log-level", validLogLevel)
+
+	// mỗi component đăng ký vào set tạm, rồi được gộp vào set của service (kèm namespace nếu bật)
+	var errs []error
 	for _, c := range s.components {
-		c.InitFlags()
+		tmp := NewFlagSet(c.ID(), flag.NewFlagSet(c.ID(), flag.ContinueOnError), "")
+		if r, ok := c.(FlagRegistrar); ok {
+			r.RegisterFlags(tmp.FlagSet)
+		} else if err := initCommandLineFlags(c, tmp); err != nil {
+			errs = append(errs, err)
+		}
+		errs = append(errs, s.adoptFlags(c, tmp)...)
 	}
+	// constraint theo tên cuối cùng (WithFlagConstraints)
+	for name, cs := range s.constraints {
+		s.cmdLine.Constrain(name, cs...)
+	}
+	return errors.Join(errs...)
 }
 
-func (s *serviceCtx) parseFlags() error {
-	s.cmdLine.Parse([]string{})
-	envFile := s.envFile
-	if envFile == "" {
-		if v := os.Getenv("ENV_FILE"); v != "" {
-			envFile = v
-		} else {
-			envFile = ".env"
+// adoptFlags chuyển flag của component từ tmp sang set của service. Khi bật WithComponentNamespaces,
+// flag được đặt dưới namespace là ID component (trừ khi tên đã bắt đầu bằng ID).
+func (s *serviceCtx) adoptFlags(c Component, tmp *AppFlagSet) []error {
+	fs := s.cmdLine.FlagSet
+	s.cmdLine.bindErrs = append(s.cmdLine.bindErrs, tmp.bindErrs...)
+	constraints, secrets := tmp.constraints, tmp.secrets
+
+	var errs []error
+	tmp.VisitAll(func(f *flag.Flag) {
+		name := f.Name
+		if s.namespaces {
+			name = namespacedFlag(c.ID(), f.Name)
+		}
+		if fs.Lookup(name) != nil {
+			owner := s.flagOwners[name]
+			if owner == "" {
+				owner = "service"
+			}
+			errs = append(errs, fmt.Errorf("sctx: flag -%s of %s already registered by %s", name, c.ID(), owner))
+			return
+		}
+		fs.Var(f.Value, name, f.Usage)
+		s.flagOwners[name] = c.ID()
+		if name != f.Name {
+			s.flagLocal[name] = f.Name
+		}
+		if secrets[f.Name] {
+			s.cmdLine.MarkSecret(name)
 		}
+		s.cmdLine.Constrain(name, constraints[f.Name]...)
+		delete(constraints, f.Name)
+	})
+	// constraint cho flag không thuộc component (vd: -app-env) giữ nguyên tên
+	for name, cs := range constraints {
+		s.cmdLine.Constrain(name, cs...)
 	}
+	return errs
+}
 
-	if st, err := os.Stat(envFile); err == nil && !st.IsDir() {
-		if err := godotenv.Load(envFile); err != nil {
-			return err
+// namespacedFlag: ("replica", "postgres-uri") -> "replica.postgres-uri"; ("postgres", "postgres-uri") giữ nguyên
+func namespacedFlag(id, name string) string {
+	ns, key := configKey(id), configKey(name)
+	if key == ns || strings.HasPrefix(key, ns+"_") {
+		return name
+	}
+	return id + "." + name
+}
+
+// initCommandLineFlags chạy InitFlags kiểu cũ: flag được đăng ký trên flag.CommandLine toàn cục
+// rồi chép sang tmp. Flag trùng tên (vd: hai service cùng dùng một component) được báo lỗi thay vì panic;
+// component cần chạy trong nhiều service một process phải dùng FlagRegistrar.
+func initCommandLineFlags(c Component, tmp *AppFlagSet) (err error) {
+	commandLineMu.Lock()
+	defer commandLineMu.Unlock()
+	known := make(map[string]bool)
+	flag.CommandLine.VisitAll(func(f *flag.Flag) { known[f.Name] = true })
+	commandLineSet.Store(tmp)
+	defer commandLineSet.Store(nil)
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("sctx: %s InitFlags: %v (implement FlagRegistrar to register on the service flag set)", c.ID(), r)
 		}
-	} else if envFile != ".env" {
+		flag.CommandLine.VisitAll(func(f *flag.Flag) {
+			if !known[f.Name] {
+				tmp.Var(f.Value, f.Name, f.Usage)
+			}
+		})
+	}()
+	c.InitFlags()
+	return nil
+}
+
+func (s *serviceCtx) parseFlags() error {
+	// env-file / config-file phải biết trước khi nạp file, nên đọc thẳng từ args
+	if v, ok := argValue(s.args, "env-file"); ok {
+		s.envFile = v
+	}
+	if v, ok := argValue(s.args, "config-file"); ok {
+		s.configFile = v
+	}
+
+	if err := s.loadEnvFiles(); err != nil {
 		return err
 	}
-	return nil
+
+	// config file và SecretSource nằm dưới .env và ENV: nạp trước, rồi mới áp ENV → flag → command line
+	if err := s.loadConfigFile(); err != nil {
+		return err
+	}
+	if err := s.loadSecrets(); err != nil {
+		return err
+	}
+	if err := s.cmdLine.Parse(s.args); err != nil {
+		return fmt.Errorf("sctx: parse flags: %w", err)
+	}
+	return s.recordSources()
 }
 
 func (s *serviceCtx) Get(id string) (any, bool) {
@@ -107,51 +300,121 @@ func (s *serviceCtx) Logger(prefix string) Logger {
 	return s.logger.WithPrefix(prefix)
 }
 
-func (s *serviceCtx) Load() error {
+func (s *serviceCtx) Load() error { return s.LoadContext(context.Background()) }
+
+// LoadContext activate các component với ctx (deadline, cancel, values đều được truyền vào Activate).
+// Khi ctx bị huỷ giữa chừng, các component đã active được rollback với ctx không bị huỷ.
+func (s *serviceCtx) LoadContext(ctx context.Context) error {
+	s.lifeMu.Lock()
+	defer s.lifeMu.Unlock()
+	if s.isLoaded() {
+		return nil
+	}
+
 	s.logger.Info("Service context is loading...")
+	if s.logConfigOn {
+		s.logConfig()
+	}
 
-	sort.SliceStable(s.components, func(i, j int) bool {
-		return componentOrder(s.components[i]) < componentOrder(s.components[j])
-	})
-	ctx := context.Background()
-	activated := make([]Component, 0, len(s.components))
+	if err := s.validate(); err != nil {
+		s.logger.Error("Invalid config: %v", err)
+		return err
+	}
 
-	for _, c := range s.components {
-		if err := c.Activate(ctx, s); err != nil {
+	order, err := resolveOrder(s.components)
+	if err != nil {
+		s.logger.Error("Cannot resolve component order: %v", err)
+		return err
+	}
+	s.order = order
+	s.levels = resolveLevels(order)
+	s.runCtx = context.WithoutCancel(ctx)
+
+	if s.parallel {
+		err = s.loadParallel(ctx, s.levels)
+	} else {
+		err = s.loadSequential(ctx, order)
+	}
+	if err != nil {
+		return err
+	}
+	s.loaded.Store(true)
+	s.logger.Info("Service context loaded")
+	return nil
+}
+
+func (s *serviceCtx) loadSequential(ctx context.Context, order []Component) error {
+	for _, c := range order {
+		err := ctx.Err()
+		if err == nil {
+			err = s.activateComponent(ctx, c)
+		}
+		if err != nil {
 			s.logger.Error("Activate failed for %s: %v; rolling back", c.ID(), err)
-			for k := len(activated) - 1; k >= 0; k-- {
-				_ = activated[k].Stop(ctx)
-			}
+			_ = s.stopSequential(context.WithoutCancel(ctx), order)
 			return err
 		}
-		activated = append(activated, c)
 	}
-	s.logger.Info("Service context loaded")
 	return nil
 }
 
-func (s *serviceCtx) Stop() error {
+func (s *serviceCtx) Stop() error { return s.StopContext(context.Background()) }
+
+// StopContext chỉ dừng các component đang active; gọi lại khi không còn gì active là no-op.
+func (s *serviceCtx) StopContext(ctx context.Context) error {
+	s.lifeMu.Lock()
+	defer s.lifeMu.Unlock()
+	s.loaded.Store(false)
+	if !s.hasActive() {
+		return nil
+	}
+
 	s.logger.Info("Stopping service context")
-	ctx := context.Background()
 
+	var err error
+	if s.parallel {
+		err = s.stopParallel(ctx, s.levels)
+	} else {
+		err = s.stopSequential(ctx, s.order)
+	}
+	s.logger.Info("Service context stopped")
+	// log sau khi stop mở lại file; RunApps/Execute đóng nó lần cuối qua closeLogSinks
+	s.closeLogSinks()
+	return err
+}
+
+func (s *serviceCtx) closeLogSinks() {
+	if s.logFile != nil {
+		_ = s.logFile.Close()
+	}
+}
+
+func (s *serviceCtx) stopSequential(ctx context.Context, order []Component) error {
 	var errs []error
-	for i := len(s.components) - 1; i >= 0; i-- {
-		if err := s.components[i].Stop(ctx); err != nil {
-			s.logger.Error("Stop %s error: %v", s.components[i].ID(), err)
+	for i := len(order) - 1; i >= 0; i-- {
+		if err := s.stopComponent(ctx, order[i]); err != nil {
+			s.logger.Error("Stop %s error: %v", order[i].ID(), err)
 			errs = append(errs, err)
 		}
 	}
-	s.logger.Info("Service context stopped")
-	if len(errs) > 0 {
-		return errors.Join(errs...)
-	}
-	return nil
+	return errors.Join(errs...)
 }
 
+func (s *serviceCtx) isLoaded() bool { return s.loaded.Load() }
+
 func (s *serviceCtx) GetName() string { return s.name }
 func (s *serviceCtx) EnvName() string { return s.env }
 func (s *serviceCtx) OutEnv()         { s.cmdLine.GetSampleEnvs() }
 
+func (s *serviceCtx) WriteEnv(w io.Writer, format EnvFormat) error {
+	return s.cmdLine.WriteEnv(w, format)
+}
+
+func (s *serviceCtx) parseErr() error { return s.initErr }
+
+// Args trả về các arg còn lại sau flag: tên subcommand và tham số của nó.
+func (s *serviceCtx) Args() []string { return s.cmdLine.Args() }
+
 func GetAs[T any](sv ServiceContext, id string) (T, bool) {
 	var zero T
 	v, ok := sv.Get(id)
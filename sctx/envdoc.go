@@ -0,0 +1,202 @@
+package sctx
+
+import (
+	"bufio"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// EnvFormat là định dạng tài liệu biến môi trường sinh từ các flag đã đăng ký.
+type EnvFormat string
+
+const (
+	EnvFormatDotenv     EnvFormat = "dotenv"      // .env.example
+	EnvFormatMarkdown   EnvFormat = "markdown"    // bảng Markdown
+	EnvFormatJSONSchema EnvFormat = "json-schema" // JSON Schema, key là tên ENV
+)
+
+// WriteEnv ghi tài liệu cho mọi flag đã đăng ký theo format: tên ENV (envNameFor),
+// giá trị mặc định, mô tả. Giá trị của flag nhạy cảm bị che.
+func (a *AppFlagSet) WriteEnv(w io.Writer, format EnvFormat) error {
+	switch format {
+	case EnvFormatDotenv, "":
+		return a.writeDotenv(w)
+	case EnvFormatMarkdown:
+		return a.writeMarkdown(w)
+	case EnvFormatJSONSchema:
+		return a.writeJSONSchema(w)
+	default:
+		return fmt.Errorf("sctx: unknown env format %q (want dotenv, markdown or json-schema)", format)
+	}
+}
+
+func (a *AppFlagSet) writeDotenv(w io.Writer) error {
+	bw := bufio.NewWriter(w)
+	_, _ = fmt.Fprintf(bw, "# Environment for %s, generated from registered flags.\n", a.appName)
+	_, _ = fmt.Fprintln(bw, "# Precedence: defaults < config file < .env < environment < command line.")
+	a.VisitAll(func(f *flag.Flag) {
+		_, _ = fmt.Fprintln(bw)
+		if f.Usage != "" {
+			_, _ = fmt.Fprintf(bw, "# %s\n", f.Usage)
+		}
+		_, _ = fmt.Fprintf(bw, "# flag: -%s%s\n", f.Name, a.flagNotes(f))
+		_, _ = fmt.Fprintf(bw, "%s=%s\n", a.envNameFor(f.Name), dotenvQuote(a.redact(f, f.DefValue)))
+	})
+	return bw.Flush()
+}
+
+func (a *AppFlagSet) writeMarkdown(w io.Writer) error {
+	bw := bufio.NewWriter(w)
+	_, _ = fmt.Fprintln(bw, "| Env | Flag | Type | Default | Description |")
+	_, _ = fmt.Fprintln(bw, "|-----|------|------|---------|-------------|")
+	a.VisitAll(func(f *flag.Flag) {
+		def := a.redact(f, f.DefValue)
+		if def != "" {
+			def = "`" + def + "`"
+		}
+		usage := f.Usage
+		if notes := a.flagNotes(f); notes != "" {
+			usage += " _" + strings.TrimPrefix(notes, " ") + "_"
+		}
+		_, _ = fmt.Fprintf(bw, "| `%s` | `-%s` | %s | %s | %s |\n",
+			a.envNameFor(f.Name), f.Name, flagType(f), def, markdownEscape(usage))
+	})
+	return bw.Flush()
+}
+
+// writeJSONSchema mô tả biến môi trường nên mọi giá trị là string; kiểu của flag được
+// diễn đạt bằng pattern (vd: integer -> ^-?[0-9]+$).
+func (a *AppFlagSet) writeJSONSchema(w io.Writer) error {
+	type property struct {
+		Type        string `json:"type"`
+		Pattern     string `json:"pattern,omitempty"`
+		Description string `json:"description,omitempty"`
+		Default     string `json:"default,omitempty"`
+		WriteOnly   bool   `json:"writeOnly,omitempty"`
+		Flag        string `json:"x-flag"`
+	}
+	props := make(map[string]property)
+	required := []string{}
+	a.VisitAll(func(f *flag.Flag) {
+		env := a.envNameFor(f.Name)
+		p := property{Type: "string", Pattern: envPattern(f), Description: f.Usage, Flag: "-" + f.Name}
+		if a.isSecret(f) {
+			p.WriteOnly = true
+		} else {
+			p.Default = a.redact(f, f.DefValue)
+		}
+		if a.isRequired(f) {
+			required = append(required, env)
+		}
+		props[env] = p
+	})
+
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(map[string]any{
+		"$schema":    "https://json-schema.org/draft/2020-12/schema",
+		"title":      a.appName,
+		"type":       "object",
+		"properties": props,
+		"required":   required,
+	})
+}
+
+// flagNotes: ghi chú thêm (required, secret) cho .env.example và Markdown
+func (a *AppFlagSet) flagNotes(f *flag.Flag) string {
+	var notes []string
+	if a.isRequired(f) {
+		notes = append(notes, "required")
+	}
+	if a.isSecret(f) {
+		notes = append(notes, "secret")
+	}
+	if len(notes) == 0 {
+		return ""
+	}
+	return " (" + strings.Join(notes, ", ") + ")"
+}
+
+// isRequired: flag có tag required của Bind, hoặc constraint từ chối giá trị rỗng (Required)
+func (a *AppFlagSet) isRequired(f *flag.Flag) bool {
+	if bv, ok := f.Value.(*boundValue); ok && bv.required {
+		return true
+	}
+	for _, c := range a.constraints[f.Name] {
+		if c("") != nil {
+			return true
+		}
+	}
+	return false
+}
+
+// flagType đoán kiểu JSON Schema của flag
+func flagType(f *flag.Flag) string {
+	if bv, ok := f.Value.(*boundValue); ok {
+		if bv.v.Type() == durationType {
+			return "string"
+		}
+		switch bv.v.Kind() {
+		case reflect.Bool:
+			return "boolean"
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+			reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+			return "integer"
+		case reflect.Float32, reflect.Float64:
+			return "number"
+		}
+		return "string"
+	}
+	if bf, ok := f.Value.(interface{ IsBoolFlag() bool }); ok && bf.IsBoolFlag() {
+		return "boolean"
+	}
+	switch {
+	case looksLikeDurationFlag(f):
+		return "string"
+	case looksLikeIntFlag(f):
+		return "integer"
+	case looksLikeFloatFlag(f):
+		return "number"
+	}
+	return "string"
+}
+
+// envPattern: regexp cho giá trị env hợp lệ theo kiểu flag (rỗng với string)
+func envPattern(f *flag.Flag) string {
+	if isDurationFlag(f) {
+		return `^(0|-?(([0-9]+(\.[0-9]*)?|\.[0-9]+)(ns|us|µs|ms|s|m|h))+)$`
+	}
+	switch flagType(f) {
+	case "boolean":
+		return `^(1|0|t|f|T|F|true|false|TRUE|FALSE|True|False)$`
+	case "integer":
+		return `^-?[0-9]+$`
+	case "number":
+		return `^-?([0-9]+(\.[0-9]*)?|\.[0-9]+)([eE][-+]?[0-9]+)?$`
+	}
+	return ""
+}
+
+func isDurationFlag(f *flag.Flag) bool {
+	if bv, ok := f.Value.(*boundValue); ok {
+		return bv.v.Type() == durationType
+	}
+	return looksLikeDurationFlag(f)
+}
+
+// dotenvQuote đặt giá trị trong "..." khi có ký tự godotenv hiểu khác đi
+func dotenvQuote(v string) string {
+	if strings.ContainsAny(v, " \t#\"'\\\n") {
+		return strconv.Quote(v)
+	}
+	return v
+}
+
+func markdownEscape(s string) string {
+	return strings.NewReplacer("|", "\\|", "\n", " ").Replace(s)
+}
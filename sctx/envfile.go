@@ -0,0 +1,95 @@
+package sctx
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/joho/godotenv"
+)
+
+// explicitEnvFiles trả về các file trong -env-file / ENV_FILE (danh sách cách nhau bởi dấu phẩy).
+func (s *serviceCtx) explicitEnvFiles() []string {
+	list := s.envFile
+	if list == "" {
+		list = os.Getenv(s.cmdLine.envNameFor("env-file"))
+	}
+	var files []string
+	for _, f := range strings.Split(list, ",") {
+		if f = strings.TrimSpace(f); f != "" {
+			files = append(files, f)
+		}
+	}
+	return files
+}
+
+// readEnvFiles đọc lần lượt các file và gộp biến (file sau thắng). File không tồn tại
+// chỉ là lỗi khi required; loaded là các file thực sự được đọc.
+func readEnvFiles(files []string, required bool) (vars map[string]string, loaded []string, err error) {
+	vars = make(map[string]string)
+	for _, path := range files {
+		st, err := os.Stat(path)
+		if err != nil {
+			if !required && os.IsNotExist(err) {
+				continue
+			}
+			return nil, nil, fmt.Errorf("sctx: env file %s: %w", path, err)
+		}
+		if st.IsDir() {
+			if !required {
+				continue
+			}
+			return nil, nil, fmt.Errorf("sctx: env file %s is a directory", path)
+		}
+		m, err := godotenv.Read(path)
+		if err != nil {
+			return nil, nil, fmt.Errorf("sctx: env file %s: %w", path, err)
+		}
+		for k, v := range m {
+			vars[k] = v
+		}
+		loaded = append(loaded, path)
+	}
+	return vars, loaded, nil
+}
+
+// loadEnvFiles nạp env file vào os env, file sau ghi đè file trước nhưng biến môi trường thật
+// luôn thắng: .env < .env.<app-env> < .env.local < ENV. app-env lấy từ command line, ENV,
+// rồi APP_ENV trong .env. Khi -env-file / ENV_FILE được set, chỉ các file đó được nạp
+// (theo thứ tự) và file nào thiếu là lỗi.
+func (s *serviceCtx) loadEnvFiles() error {
+	if files := s.explicitEnvFiles(); len(files) > 0 {
+		vars, loaded, err := readEnvFiles(files, true)
+		if err != nil {
+			return err
+		}
+		s.envFiles = loaded
+		s.syncFileEnv(vars)
+		return nil
+	}
+
+	vars, loaded, err := readEnvFiles([]string{".env"}, false)
+	if err != nil {
+		return err
+	}
+	appEnv, ok := argValue(s.args, "app-env")
+	if !ok {
+		appEnv = os.Getenv(s.cmdLine.envNameFor("app-env"))
+	}
+	if appEnv == "" {
+		appEnv = vars[s.cmdLine.envNameFor("app-env")]
+	}
+	if appEnv == "" {
+		appEnv = DevEnv
+	}
+	layers, more, err := readEnvFiles([]string{".env." + appEnv, ".env.local"}, false)
+	if err != nil {
+		return err
+	}
+	for k, v := range layers {
+		vars[k] = v
+	}
+	s.envFiles = append(loaded, more...)
+	s.syncFileEnv(vars)
+	return nil
+}
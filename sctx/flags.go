@@ -1,19 +1,29 @@
 package sctx
 
 import (
+	"errors"
 	"flag"
 	"fmt"
+	"io"
+	"net/url"
 	"os"
+	"slices"
 	"strconv"
 	"strings"
+	"sync"
+	"sync/atomic"
 	"time"
 )
 
 // AppFlagSet bọc flag.FlagSet + hỗ trợ ENV
 type AppFlagSet struct {
 	*flag.FlagSet
-	appName   string
-	envPrefix string // ví dụ: "APP_" => APP_GIN_PORT
+	appName     string
+	envPrefix   string                  // ví dụ: "APP_" => APP_GIN_PORT
+	secrets     map[string]bool         // flag đánh dấu secret qua MarkSecret
+	constraints map[string][]Constraint // constraint theo tên flag, xem Constrain
+	bindErrs    []error                 // lỗi Bind, trả về từ Load thay vì panic
+	out         io.Writer               // output gốc của FlagSet, xem Write
 }
 
 // NewFlagSet tạo AppFlagSet. Nếu fs == nil -> dùng flag.CommandLine
@@ -27,21 +37,62 @@ func NewFlagSet(appName string, fs *flag.FlagSet, envPrefix string) *AppFlagSet
 		envPrefix: envPrefix,
 	}
 	a.Usage = a.customUsage()
+	if fs != flag.CommandLine {
+		a.out = fs.Output()
+		fs.SetOutput(a)
+	}
 	return a
 }
 
-// Parse: apply ENV → flag trước, rồi parse args.
+// Parse: apply ENV → flag trước, rồi parse args (command line thắng ENV).
 // args thường để []string{} nếu bạn muốn bỏ qua os.Args.
-func (a *AppFlagSet) Parse(args []string) {
-	a.applyEnvOverrides()
-	_ = a.FlagSet.Parse(args)
+func (a *AppFlagSet) Parse(args []string) error {
+	envErr := a.applyEnvOverrides()
+	return errors.Join(envErr, a.FlagSet.Parse(args))
+}
+
+// MarkSecret đánh dấu flag name là secret: giá trị bị che trong usage, config dump và log.
+func (a *AppFlagSet) MarkSecret(name string) {
+	if a.secrets == nil {
+		a.secrets = make(map[string]bool)
+	}
+	a.secrets[name] = true
+}
+
+// Constrain gắn constraint cho flag name, kiểm tra trong Load.
+func (a *AppFlagSet) Constrain(name string, cs ...Constraint) {
+	if a.constraints == nil {
+		a.constraints = make(map[string][]Constraint)
+	}
+	a.constraints[name] = append(a.constraints[name], cs...)
+}
+
+// InitFlags kiểu cũ đăng ký trên flag.CommandLine toàn cục; trong lúc sctx gọi nó
+// (xem initCommandLineFlags), commandLineSet nhận lỗi Bind, constraint và secret ghi trên flag.CommandLine.
+var (
+	commandLineMu  sync.Mutex
+	commandLineSet atomic.Pointer[AppFlagSet]
+)
+
+// appFlagSetOf trả về AppFlagSet bọc fs, để các hàm nhận *flag.FlagSet (BindFlagSet,
+// ConstrainFlagSet, MarkSecretFlagSet) ghi vào đó; nil nếu fs không do sctx tạo.
+func appFlagSetOf(fs *flag.FlagSet) *AppFlagSet {
+	if fs == flag.CommandLine {
+		return commandLineSet.Load()
+	}
+	a, _ := fs.Output().(*AppFlagSet)
+	return a
+}
+
+// Write chuyển output của FlagSet (lỗi parse, usage) tới output gốc. AppFlagSet được gắn làm
+// output của FlagSet nó bọc để appFlagSetOf tìm lại từ *flag.FlagSet mà không cần state toàn cục.
+func (a *AppFlagSet) Write(p []byte) (int, error) {
+	return a.out.Write(p)
 }
 
-// GetSampleEnvs: in gợi ý biến ENV phổ biến
+// GetSampleEnvs: in .env.example đầy đủ cho mọi flag đã đăng ký ra stdout
 func (a *AppFlagSet) GetSampleEnvs() {
-	fmt.Println("# Sample ENVs")
-	fmt.Println("APP_ENV=dev           # dev|stg|prd")
-	fmt.Println("ENV_FILE=.env         # đường dẫn .env")
+	_ = a.WriteEnv(os.Stdout, EnvFormatDotenv)
 }
 
 // ====== internal ======
@@ -65,16 +116,16 @@ func (a *AppFlagSet) customUsage() func() {
 			// default value
 			if !isZeroValue(f, f.DefValue) {
 				if isStringFlag(f) {
-					line += fmt.Sprintf(" (default %q)", f.DefValue)
+					line += fmt.Sprintf(" (default %q)", a.redact(f, f.DefValue))
 				} else {
-					line += fmt.Sprintf(" (default %v)", f.DefValue)
+					line += fmt.Sprintf(" (default %v)", a.redact(f, f.DefValue))
 				}
 			}
 
 			// ENV name + current ENV (nếu có)
 			envName := a.envNameFor(f.Name)
 			if v, ok := os.LookupEnv(envName); ok && strings.TrimSpace(v) != "" {
-				line += fmt.Sprintf(" [$%s=%q]", envName, v)
+				line += fmt.Sprintf(" [$%s=%q]", envName, a.redact(f, v))
 			} else {
 				line += fmt.Sprintf(" [$%s]", envName)
 			}
@@ -84,19 +135,71 @@ func (a *AppFlagSet) customUsage() func() {
 	}
 }
 
-func (a *AppFlagSet) applyEnvOverrides() {
+func (a *AppFlagSet) applyEnvOverrides() error {
+	var errs []error
 	a.VisitAll(func(f *flag.Flag) {
-		envName := a.envNameFor(f.Name)
-		envVal, ok := os.LookupEnv(envName)
-		if !ok || strings.TrimSpace(envVal) == "" {
+		envVal, ok, err := lookupEnvFile(os.LookupEnv, a.envNameFor(f.Name))
+		if err != nil {
+			errs = append(errs, err)
 			return
 		}
-		// Set theo kiểu flag
-		_ = setFlagValue(a.FlagSet, f, envVal)
+		if !ok {
+			return
+		}
+		// Set theo kiểu flag; lỗi parse báo như lỗi của command line, kèm tên biến env
+		if err := setFlagValue(a.FlagSet, f, envVal); err != nil {
+			errs = append(errs, fmt.Errorf("sctx: $%s: invalid value %q for flag -%s: %w",
+				a.envNameFor(f.Name), a.redact(f, envVal), f.Name, err))
+		}
 	})
+	return errors.Join(errs...)
+}
+
+// lookupEnvFile đọc biến NAME, nếu trống thì đọc nội dung file tại NAME_FILE (Docker secrets).
+func lookupEnvFile(lookup func(string) (string, bool), name string) (string, bool, error) {
+	if v, ok := lookup(name); ok && strings.TrimSpace(v) != "" {
+		return v, true, nil
+	}
+	path, ok := lookup(name + "_FILE")
+	if !ok || strings.TrimSpace(path) == "" {
+		return "", false, nil
+	}
+	v, err := readSecretFile(path)
+	if err != nil {
+		return "", false, fmt.Errorf("sctx: $%s_FILE: %w", name, err)
+	}
+	return v, true, nil
+}
+
+// argValue tìm giá trị của flag name trong args (-name v, -name=v, --name...) mà không parse cả set;
+// dùng cho các flag cần biết trước khi nạp file (env-file, config-file).
+func argValue(args []string, name string) (string, bool) {
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		if arg == "--" {
+			break
+		}
+		if !strings.HasPrefix(arg, "-") {
+			continue
+		}
+		arg = strings.TrimPrefix(strings.TrimPrefix(arg, "-"), "-")
+		if v, ok := strings.CutPrefix(arg, name+"="); ok {
+			return v, true
+		}
+		if arg == name && i+1 < len(args) {
+			return args[i+1], true
+		}
+	}
+	return "", false
 }
 
 func (a *AppFlagSet) envNameFor(name string) string {
+	// flag khai báo tên env riêng (vd: tag env của Bind)
+	if f := a.Lookup(name); f != nil {
+		if e, ok := f.Value.(interface{ EnvName() string }); ok && e.EnvName() != "" {
+			return e.EnvName()
+		}
+	}
 	name = strings.ReplaceAll(name, ".", "_")
 	name = strings.ReplaceAll(name, "-", "_")
 	name = strings.ToUpper(name)
@@ -108,6 +211,63 @@ func (a *AppFlagSet) envNameFor(name string) string {
 
 // ====== helpers ======
 
+const redacted = "******"
+
+// secretNameHints so khớp nguyên đoạn của tên flag (tách bởi - _ .), không phải chuỗi con:
+// "db-password", "auth.token", "stripe-api-key" là secret; "cache-key", "passive", "bypass" thì không.
+var secretNameHints = [][]string{
+	{"password"}, {"passwd"}, {"pass"}, {"secret"}, {"token"}, {"credential"}, {"credentials"},
+	{"apikey"}, {"api", "key"}, {"private", "key"}, {"access", "key"},
+}
+
+// isSecretName: tên flag chứa một secretNameHints
+func isSecretName(name string) bool {
+	segs := strings.FieldsFunc(strings.ToLower(name), func(r rune) bool { return r == '-' || r == '_' || r == '.' })
+	for _, h := range secretNameHints {
+		for i := 0; i+len(h) <= len(segs); i++ {
+			if slices.Equal(segs[i:i+len(h)], h) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// redactValue che giá trị của flag có tên nhạy cảm, và password nằm trong URI (vd: postgres://u:p@host)
+func redactValue(name, v string) string {
+	if v == "" {
+		return v
+	}
+	if isSecretName(name) {
+		return redacted
+	}
+	if u, err := url.Parse(v); err == nil && u.User != nil {
+		if _, ok := u.User.Password(); ok {
+			return u.Redacted()
+		}
+	}
+	return v
+}
+
+// isSecret: flag đánh dấu secret (MarkSecret, tag secret của Bind) hoặc có tên nhạy cảm
+func (a *AppFlagSet) isSecret(f *flag.Flag) bool {
+	if a.secrets[f.Name] {
+		return true
+	}
+	if sf, ok := f.Value.(interface{ IsSecret() bool }); ok && sf.IsSecret() {
+		return true
+	}
+	return isSecretName(f.Name)
+}
+
+// redact che giá trị của flag secret, còn lại theo redactValue (password trong URI)
+func (a *AppFlagSet) redact(f *flag.Flag, v string) string {
+	if a.isSecret(f) && v != "" {
+		return redacted
+	}
+	return redactValue(f.Name, v)
+}
+
 func isStringFlag(f *flag.Flag) bool {
 	// best-effort: đoán string flag qua format
 	// không dựa vào reflect để tránh panic
@@ -138,9 +298,9 @@ func isZeroValue(f *flag.Flag, def string) bool {
 // setFlagValue cố gắng parse theo kiểu phổ biến; cuối cùng fallback Set(raw)
 func setFlagValue(fs *flag.FlagSet, f *flag.Flag, raw string) error {
 	// bool
-	if _, ok := fs.Lookup(f.Name).Value.(interface {
+	if bf, ok := fs.Lookup(f.Name).Value.(interface {
 		IsBoolFlag() bool
-	}); ok {
+	}); ok && bf.IsBoolFlag() {
 		if raw == "" {
 			raw = "true"
 		}
@@ -164,8 +324,8 @@ func setFlagValue(fs *flag.FlagSet, f *flag.Flag, raw string) error {
 
 // heuristics nhận diện kiểu flag (tránh reflect)
 func looksLikeDurationFlag(f *flag.Flag) bool {
-	tv := fmt.Sprintf("%T", f.Value)
-	return strings.Contains(tv, "Duration")
+	tv := fmt.Sprintf("%T", f.Value) // *flag.durationValue hoặc kiểu riêng tên *Duration
+	return strings.Contains(tv, "Duration") || strings.Contains(tv, "duration")
 }
 func looksLikeIntFlag(f *flag.Flag) bool {
 	tv := fmt.Sprintf("%T", f.Value)
@@ -0,0 +1,151 @@
+package sctx
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+var (
+	ErrMissingDependency = errors.New("sctx: missing dependency")
+	ErrDependencyCycle   = errors.New("sctx: dependency cycle")
+)
+
+// Dependent là interface tuỳ chọn: component khai báo ID các component nó phụ thuộc.
+// Component phụ thuộc luôn được Activate sau và Stop trước các dependency của nó.
+type Dependent interface {
+	DependsOn() []string
+}
+
+func componentDeps(c Component) []string {
+	if d, ok := any(c).(Dependent); ok {
+		return d.DependsOn()
+	}
+	return nil
+}
+
+// resolveOrder trả về thứ tự khởi động theo đồ thị phụ thuộc (topological sort).
+// Giữa các component đã sẵn sàng, Order() rồi thứ tự đăng ký được dùng làm tiebreaker.
+func resolveOrder(cs []Component) ([]Component, error) {
+	index := make(map[string]int, len(cs))
+	for i, c := range cs {
+		index[c.ID()] = i
+	}
+
+	indegree := make([]int, len(cs))
+	dependents := make([][]int, len(cs))
+	for i, c := range cs {
+		seen := make(map[string]bool)
+		for _, dep := range componentDeps(c) {
+			if seen[dep] {
+				continue
+			}
+			seen[dep] = true
+			j, ok := index[dep]
+			if !ok {
+				return nil, fmt.Errorf("%w: %s depends on %q which is not registered", ErrMissingDependency, c.ID(), dep)
+			}
+			if j == i {
+				return nil, fmt.Errorf("%w: %s -> %s", ErrDependencyCycle, c.ID(), c.ID())
+			}
+			indegree[i]++
+			dependents[j] = append(dependents[j], i)
+		}
+	}
+
+	less := func(a, b int) bool {
+		oa, ob := componentOrder(cs[a]), componentOrder(cs[b])
+		if oa != ob {
+			return oa < ob
+		}
+		return a < b
+	}
+
+	ready := make([]int, 0, len(cs))
+	for i := range cs {
+		if indegree[i] == 0 {
+			ready = append(ready, i)
+		}
+	}
+
+	order := make([]Component, 0, len(cs))
+	for len(ready) > 0 {
+		sort.Slice(ready, func(x, y int) bool { return less(ready[x], ready[y]) })
+		i := ready[0]
+		ready = ready[1:]
+		order = append(order, cs[i])
+		for _, k := range dependents[i] {
+			indegree[k]--
+			if indegree[k] == 0 {
+				ready = append(ready, k)
+			}
+		}
+	}
+
+	if len(order) != len(cs) {
+		return nil, fmt.Errorf("%w: %s", ErrDependencyCycle, strings.Join(findCycle(cs, index, indegree), " -> "))
+	}
+	return order, nil
+}
+
+// findCycle lần theo các component còn indegree > 0 để in ra một chu trình cụ thể.
+func findCycle(cs []Component, index map[string]int, indegree []int) []string {
+	start := -1
+	for i := range cs {
+		if indegree[i] > 0 {
+			start = i
+			break
+		}
+	}
+	if start < 0 {
+		return nil
+	}
+
+	pos := make(map[int]int)
+	var path []int
+	cur := start
+	for {
+		if p, ok := pos[cur]; ok {
+			ids := make([]string, 0, len(path)-p+1)
+			for _, i := range path[p:] {
+				ids = append(ids, cs[i].ID())
+			}
+			return append(ids, cs[cur].ID())
+		}
+		pos[cur] = len(path)
+		path = append(path, cur)
+		next := -1
+		for _, dep := range componentDeps(cs[cur]) {
+			if j := index[dep]; indegree[j] > 0 {
+				next = j
+				break
+			}
+		}
+		if next < 0 {
+			return []string{cs[start].ID()}
+		}
+		cur = next
+	}
+}
+
+// resolveLevels chia thứ tự đã sắp xếp thành các tầng: mọi component trong cùng tầng
+// không phụ thuộc nhau, và mọi dependency nằm ở tầng thấp hơn.
+func resolveLevels(order []Component) [][]Component {
+	level := make(map[string]int, len(order))
+	var levels [][]Component
+	for _, c := range order {
+		l := 0
+		for _, dep := range componentDeps(c) {
+			if dl, ok := level[dep]; ok && dl+1 > l {
+				l = dl + 1
+			}
+		}
+		level[c.ID()] = l
+		if l == len(levels) {
+			levels = append(levels, nil)
+		}
+		levels[l] = append(levels[l], c)
+	}
+	return levels
+}
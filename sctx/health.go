@@ -0,0 +1,142 @@
+package sctx
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+type HealthStatus string
+
+const (
+	HealthUp   HealthStatus = "up"
+	HealthDown HealthStatus = "down"
+)
+
+// DefaultHealthTimeout giới hạn thời gian cho mỗi lần check nếu ctx không có deadline
+const DefaultHealthTimeout = 5 * time.Second
+
+var ErrNotLoaded = errors.New("sctx: service context not loaded")
+
+// HealthChecker là interface tuỳ chọn: component báo liveness (vd: ping DB, kết nối broker).
+type HealthChecker interface {
+	HealthCheck(ctx context.Context) error
+}
+
+// ReadinessChecker là interface tuỳ chọn khi readiness khác liveness
+// (vd: đang warm-up cache). Nếu không implement, HealthCheck được dùng cho cả hai.
+type ReadinessChecker interface {
+	ReadinessCheck(ctx context.Context) error
+}
+
+type ComponentHealth struct {
+	ID      string        `json:"id"`
+	Status  HealthStatus  `json:"status"`
+	Latency time.Duration `json:"latency"`
+	Error   string        `json:"error,omitempty"`
+}
+
+func (h ComponentHealth) MarshalJSON() ([]byte, error) {
+	type alias ComponentHealth
+	return json.Marshal(struct {
+		alias
+		Latency string `json:"latency"`
+	}{alias: alias(h), Latency: h.Latency.String()})
+}
+
+type HealthReport struct {
+	Status     HealthStatus      `json:"status"`
+	CheckedAt  time.Time         `json:"checked_at"`
+	Components []ComponentHealth `json:"components"`
+}
+
+func (r HealthReport) Healthy() bool { return r.Status == HealthUp }
+
+// Liveness chỉ gọi HealthCheck của component đang active; component có check nhưng chưa Activate
+// xong hoặc đã Stop được báo down (check của nó có thể đọc state chưa khởi tạo).
+func (s *serviceCtx) Liveness(ctx context.Context) HealthReport {
+	return s.checkHealth(ctx, func(c Component) func(context.Context) error {
+		hc, ok := c.(HealthChecker)
+		if !ok {
+			return nil
+		}
+		return func(ctx context.Context) error {
+			if st := s.stateOf(c.ID()); st != StateActive {
+				return fmt.Errorf("component is %s", st)
+			}
+			return hc.HealthCheck(ctx)
+		}
+	})
+}
+
+func (s *serviceCtx) Readiness(ctx context.Context) HealthReport {
+	if !s.isLoaded() {
+		return HealthReport{
+			Status:     HealthDown,
+			CheckedAt:  time.Now(),
+			Components: []ComponentHealth{{ID: s.name, Status: HealthDown, Error: ErrNotLoaded.Error()}},
+		}
+	}
+	return s.checkHealth(ctx, func(c Component) func(context.Context) error {
+		return func(ctx context.Context) error {
+			if st := s.stateOf(c.ID()); st != StateActive {
+				return fmt.Errorf("component is %s", st)
+			}
+			if rc, ok := c.(ReadinessChecker); ok {
+				return rc.ReadinessCheck(ctx)
+			}
+			if hc, ok := c.(HealthChecker); ok {
+				return hc.HealthCheck(ctx)
+			}
+			return nil
+		}
+	})
+}
+
+// checkHealth chạy check của mọi component đồng thời; component không có check coi như up.
+// Check bỏ qua ctx và treo được báo down khi hết deadline, không giữ cả report.
+func (s *serviceCtx) checkHealth(ctx context.Context, pick func(Component) func(context.Context) error) HealthReport {
+	if _, ok := ctx.Deadline(); !ok {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, DefaultHealthTimeout)
+		defer cancel()
+	}
+
+	report := HealthReport{
+		Status:     HealthUp,
+		CheckedAt:  time.Now(),
+		Components: make([]ComponentHealth, len(s.components)),
+	}
+
+	var wg sync.WaitGroup
+	for i, c := range s.components {
+		report.Components[i] = ComponentHealth{ID: c.ID(), Status: HealthUp}
+		check := pick(c)
+		if check == nil {
+			continue
+		}
+		wg.Add(1)
+		go func(h *ComponentHealth, check func(context.Context) error) {
+			defer wg.Done()
+			start := time.Now()
+			err := callWithTimeout(ctx, h.ID, PhaseHealth, 0, check, nil)
+			h.Latency = time.Since(start)
+			if err != nil {
+				h.Status = HealthDown
+				h.Error = err.Error()
+			}
+		}(&report.Components[i], check)
+	}
+	wg.Wait()
+
+	for _, h := range report.Components {
+		if h.Status != HealthUp {
+			report.Status = HealthDown
+			break
+		}
+	}
+	return report
+}
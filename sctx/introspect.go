@@ -0,0 +1,55 @@
+package sctx
+
+import "flag"
+
+type ComponentInfo struct {
+	ID        string         `json:"id"`
+	Order     int            `json:"order"`
+	DependsOn []string       `json:"depends_on,omitempty"`
+	State     ComponentState `json:"state"`
+}
+
+type ConfigEntry struct {
+	Name    string       `json:"name"`
+	Env     string       `json:"env"`
+	Value   string       `json:"value"`
+	Default string       `json:"default"`
+	Source  ConfigSource `json:"source"`
+	Usage   string       `json:"usage"`
+}
+
+// Components liệt kê component theo thứ tự activate (hoặc thứ tự đăng ký nếu chưa Load).
+func (s *serviceCtx) Components() []ComponentInfo {
+	cs := s.order
+	if cs == nil {
+		cs = s.components
+	}
+	out := make([]ComponentInfo, 0, len(cs))
+	for _, c := range cs {
+		out = append(out, ComponentInfo{
+			ID:        c.ID(),
+			Order:     componentOrder(c),
+			DependsOn: componentDeps(c),
+			State:     s.stateOf(c.ID()),
+		})
+	}
+	return out
+}
+
+// Config trả về giá trị hiệu lực của mọi flag kèm nguồn của nó, đã che các giá trị nhạy cảm.
+func (s *serviceCtx) Config() []ConfigEntry {
+	s.cfgMu.RLock()
+	defer s.cfgMu.RUnlock()
+	var out []ConfigEntry
+	s.cmdLine.VisitAll(func(f *flag.Flag) {
+		out = append(out, ConfigEntry{
+			Name:    f.Name,
+			Env:     s.cmdLine.envNameFor(f.Name),
+			Value:   s.cmdLine.redact(f, s.flagValue(f)),
+			Default: s.cmdLine.redact(f, f.DefValue),
+			Source:  s.sourceOf(f.Name),
+			Usage:   f.Usage,
+		})
+	})
+	return out
+}
@@ -0,0 +1,102 @@
+package sctx
+
+import "context"
+
+type ComponentState string
+
+const (
+	StateRegistered ComponentState = "registered"
+	StateActivating ComponentState = "activating"
+	StateActive     ComponentState = "active"
+	StateFailed     ComponentState = "failed"
+	StateStopping   ComponentState = "stopping"
+	StateStopped    ComponentState = "stopped"
+)
+
+func (s *serviceCtx) setState(id string, st ComponentState) {
+	s.stateMu.Lock()
+	defer s.stateMu.Unlock()
+	s.states[id] = st
+}
+
+// transition đổi trạng thái from -> to một cách nguyên tử, trả về false nếu trạng thái hiện tại khác from.
+func (s *serviceCtx) transition(id string, from, to ComponentState) bool {
+	s.stateMu.Lock()
+	defer s.stateMu.Unlock()
+	if s.states[id] != from {
+		return false
+	}
+	s.states[id] = to
+	return true
+}
+
+func (s *serviceCtx) stateOf(id string) ComponentState {
+	s.stateMu.RLock()
+	defer s.stateMu.RUnlock()
+	return s.states[id]
+}
+
+// States trả về bảng trạng thái lifecycle hiện tại của mọi component.
+func (s *serviceCtx) States() map[string]ComponentState {
+	s.stateMu.RLock()
+	defer s.stateMu.RUnlock()
+	out := make(map[string]ComponentState, len(s.states))
+	for id, st := range s.states {
+		out[id] = st
+	}
+	return out
+}
+
+func (s *serviceCtx) hasActive() bool {
+	s.stateMu.RLock()
+	defer s.stateMu.RUnlock()
+	for _, st := range s.states {
+		if st == StateActive {
+			return true
+		}
+	}
+	return false
+}
+
+// activateComponent: registered|stopped|failed → activating → active|failed.
+// Component đã active thì bỏ qua.
+func (s *serviceCtx) activateComponent(ctx context.Context, c Component) error {
+	if s.stateOf(c.ID()) == StateActive {
+		return nil
+	}
+	s.setState(c.ID(), StateActivating)
+	err := callWithTimeout(ctx, c.ID(), PhaseActivate, s.timeoutFor(c, PhaseActivate), func(ctx context.Context) error {
+		return c.Activate(ctx, s)
+	}, func(err error) {
+		// Activate bỏ qua ctx và xong sau deadline: dừng lại những gì nó đã mở
+		if err != nil {
+			return
+		}
+		s.logger.Warn("Component %s activated after its deadline, stopping it", c.ID())
+		if err := callWithTimeout(context.Background(), c.ID(), PhaseStop, s.timeoutFor(c, PhaseStop), c.Stop, nil); err != nil {
+			s.logger.Error("Stop %s after late activation: %v", c.ID(), err)
+		}
+	})
+	if err != nil {
+		s.setState(c.ID(), StateFailed)
+		return err
+	}
+	s.setState(c.ID(), StateActive)
+	s.startRunnable(c)
+	return nil
+}
+
+// stopComponent: active → stopping → stopped|failed. Chỉ component đang active mới được Stop;
+// Run (nếu có) được huỷ và chờ thoát trước khi gọi Stop.
+func (s *serviceCtx) stopComponent(ctx context.Context, c Component) error {
+	if !s.transition(c.ID(), StateActive, StateStopping) {
+		return nil
+	}
+	s.haltRunnable(ctx, c.ID())
+	if err := callWithTimeout(ctx, c.ID(), PhaseStop, s.timeoutFor(c, PhaseStop), c.Stop, nil); err != nil {
+		s.setState(c.ID(), StateFailed)
+		return err
+	}
+	s.setState(c.ID(), StateStopped)
+	return nil
+}
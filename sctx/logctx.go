@@ -0,0 +1,17 @@
+package sctx
+
+import (
+	"context"
+
+	"github.com/jackdes93/fcontext/logctx"
+)
+
+// ContextWithFields trả về ctx mang thêm field log (request ID, job, trace ID...);
+// logger lấy qua Logger.Ctx(ctx) gắn chúng vào mọi dòng log. Key đã có trong ctx được ghi đè.
+// Package không import sctx có thể dùng thẳng logctx.With.
+func ContextWithFields(ctx context.Context, kv ...any) context.Context {
+	return logctx.With(ctx, kv...)
+}
+
+// FieldsFromContext trả về các cặp key-value đã gắn bằng ContextWithFields (không được sửa).
+func FieldsFromContext(ctx context.Context) []any { return logctx.Fields(ctx) }
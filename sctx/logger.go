@@ -2,48 +2,116 @@
 package sctx
 
 import (
+	"context"
+	"io"
 	"os"
 	"strings"
 	"time"
 
+	"github.com/jackdes93/fcontext/logctx"
 	"github.com/rs/zerolog"
-	"github.com/rs/zerolog/log"
 )
 
+// Logger: Debug/Info/Warn/Error nhận format kiểu printf; các hàm *w nhận cặp key-value
+// (vd: Infow("subscribed", "topic", topic)) và ghi thành field có cấu trúc.
 type Logger interface {
 	Debug(msg string, args ...any)
 	Info(msg string, args ...any)
 	Warn(msg string, args ...any)
 	Error(msg string, args ...any)
+	Debugw(msg string, kv ...any)
+	Infow(msg string, kv ...any)
+	Warnw(msg string, kv ...any)
+	Errorw(msg string, kv ...any)
+	// With trả về logger luôn kèm các field kv
+	With(kv ...any) Logger
+	// Ctx trả về logger kèm các field gắn vào ctx bằng ContextWithFields
+	Ctx(ctx context.Context) Logger
 	WithPrefix(prefix string) Logger
 }
 
 type ZeroLogger struct {
 	logger zerolog.Logger
+	levels *logLevels
+	prefix string
 }
 
-func newZeroLogger(prefix, env string) *ZeroLogger {
-	zerolog.TimeFieldFormat = time.RFC3339Nano
+// nanoTimestamp ghi field time dạng RFC3339Nano cho từng logger,
+// thay vì đổi zerolog.TimeFieldFormat toàn cục (ảnh hưởng mọi logger khác trong process).
+type nanoTimestamp struct{}
+
+func (nanoTimestamp) Run(e *zerolog.Event, _ zerolog.Level, _ string) {
+	e.Str(zerolog.TimestampFieldName, time.Now().Format(time.RFC3339Nano))
+}
+
+// newZeroLogger tạo logger riêng cho từng ServiceContext, không ghi đè log.Logger toàn cục của zerolog.
+// sinks (file, ring buffer) nhận bản JSON của mọi entry; sample > 1 chỉ giữ 1/sample dòng debug.
+func newZeroLogger(prefix, env string, levels *logLevels, sample int, sinks ...io.Writer) *ZeroLogger {
+	var out io.Writer
 	switch strings.ToLower(env) {
 	case "production", "prod", "prd":
-		l := zerolog.New(os.Stdout).With().Str("service", prefix).Timestamp().Logger()
-		log.Logger = l
-		return &ZeroLogger{logger: l}
+		out = os.Stdout
 	default:
-		w := zerolog.ConsoleWriter{Out: os.Stderr, TimeFormat: time.RFC3339Nano}
-		z := log.Output(w)
-		if prefix != "" {
-			z = z.With().Str("service", prefix).Logger()
-		}
-		return &ZeroLogger{logger: z}
+		out = zerolog.ConsoleWriter{Out: os.Stderr, TimeFormat: time.RFC3339Nano}
+	}
+	if len(sinks) > 0 {
+		out = zerolog.MultiLevelWriter(append([]io.Writer{out}, sinks...)...)
+	}
+	z := zerolog.New(out).Hook(nanoTimestamp{})
+	if sample > 1 {
+		sampler := &zerolog.BasicSampler{N: uint32(sample)}
+		z = z.Sample(&zerolog.LevelSampler{TraceSampler: sampler, DebugSampler: sampler})
 	}
+	if prefix != "" {
+		z = z.With().Str("service", prefix).Logger()
+	}
+	return &ZeroLogger{logger: z, levels: levels}
+}
+
+func (l *ZeroLogger) Debug(msg string, args ...any) { l.event(zerolog.DebugLevel).Msgf(msg, args...) }
+func (l *ZeroLogger) Info(msg string, args ...any)  { l.event(zerolog.InfoLevel).Msgf(msg, args...) }
+func (l *ZeroLogger) Warn(msg string, args ...any)  { l.event(zerolog.WarnLevel).Msgf(msg, args...) }
+func (l *ZeroLogger) Error(msg string, args ...any) { l.event(zerolog.ErrorLevel).Msgf(msg, args...) }
+
+func (l *ZeroLogger) Debugw(msg string, kv ...any) { l.fields(zerolog.DebugLevel, kv).Msg(msg) }
+func (l *ZeroLogger) Infow(msg string, kv ...any)  { l.fields(zerolog.InfoLevel, kv).Msg(msg) }
+func (l *ZeroLogger) Warnw(msg string, kv ...any)  { l.fields(zerolog.WarnLevel, kv).Msg(msg) }
+func (l *ZeroLogger) Errorw(msg string, kv ...any) { l.fields(zerolog.ErrorLevel, kv).Msg(msg) }
+
+func (l *ZeroLogger) With(kv ...any) Logger {
+	return &ZeroLogger{logger: l.logger.With().Fields(fieldList(kv)).Logger(), levels: l.levels, prefix: l.prefix}
 }
 
-func (l *ZeroLogger) Debug(msg string, args ...any) { l.logger.Debug().Msgf(msg, args...) }
-func (l *ZeroLogger) Info(msg string, args ...any)  { l.logger.Info().Msgf(msg, args...) }
-func (l *ZeroLogger) Warn(msg string, args ...any)  { l.logger.Warn().Msgf(msg, args...) }
-func (l *ZeroLogger) Error(msg string, args ...any) { l.logger.Error().Msgf(msg, args...) }
+func (l *ZeroLogger) Ctx(ctx context.Context) Logger {
+	if fields := FieldsFromContext(ctx); len(fields) > 0 {
+		return l.With(fields...)
+	}
+	return l
+}
 
+// WithPrefix: level riêng của prefix (-log-level prefix=level) áp dụng cho logger trả về
 func (l *ZeroLogger) WithPrefix(prefix string) Logger {
-	return &ZeroLogger{logger: l.logger.With().Str("prefix", prefix).Logger()}
+	return &ZeroLogger{logger: l.logger.With().Str("prefix", prefix).Logger(), levels: l.levels, prefix: prefix}
+}
+
+// event trả về nil (zerolog bỏ qua mọi lệnh trên nil event) khi level bị tắt
+func (l *ZeroLogger) event(lvl zerolog.Level) *zerolog.Event {
+	if !l.levels.enabled(l.prefix, lvl) {
+		return nil
+	}
+	return l.logger.WithLevel(lvl)
 }
+
+func (l *ZeroLogger) fields(lvl zerolog.Level, kv []any) *zerolog.Event {
+	e := l.event(lvl)
+	if e == nil {
+		return nil
+	}
+	return e.Fields(fieldList(kv))
+}
+
+// badKey là key cho giá trị lẻ không có key đi kèm (giống log/slog)
+const badKey = logctx.BadKey
+
+// fieldList chuẩn hoá kv cho zerolog, xem logctx.Normalize
+func fieldList(kv []any) []any { return logctx.Normalize(kv) }
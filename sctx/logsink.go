@@ -0,0 +1,274 @@
+package sctx
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// logSinkConfig là cấu hình sink của logger mặc định, đọc từ flag -log-*.
+type logSinkConfig struct {
+	file        string
+	maxSize     int // MB
+	rotateEvery time.Duration
+	maxAge      time.Duration
+	maxBackups  int
+	sample      int
+	buffer      int
+}
+
+func (c *logSinkConfig) registerFlags(fs *flag.FlagSet) {
+	fs.StringVar(&c.file, "log-file", "", "Also write JSON logs to this file")
+	fs.IntVar(&c.maxSize, "log-file-max-size", 100, "Rotate the log file when it exceeds this size in MB (0 = never)")
+	fs.DurationVar(&c.rotateEvery, "log-file-rotate", 0, "Rotate the log file after this interval, e.g. 24h (0 = never)")
+	fs.DurationVar(&c.maxAge, "log-file-max-age", 0, "Delete rotated log files older than this (0 = keep)")
+	fs.IntVar(&c.maxBackups, "log-file-max-backups", 5, "Number of rotated log files to keep (0 = all)")
+	fs.IntVar(&c.sample, "log-sample", 0, "Keep 1 of every N debug lines (0 = all)")
+	fs.IntVar(&c.buffer, "log-buffer", 0, "Keep the last N log entries in memory for the admin API (0 = off)")
+}
+
+// openLogSinks mở các sink bổ sung (file, ring buffer) theo flag; lỗi mở file được báo từ Load.
+func (s *serviceCtx) openLogSinks() (file *rotatingFile, ring *ringBuffer, err error) {
+	c := s.logSinks
+	if c.file != "" {
+		file = &rotatingFile{
+			path:        c.file,
+			maxSize:     int64(c.maxSize) << 20,
+			rotateEvery: c.rotateEvery,
+			maxAge:      c.maxAge,
+			maxBackups:  c.maxBackups,
+			now:         time.Now,
+		}
+		if err = file.open(); err != nil {
+			file, err = nil, fmt.Errorf("sctx: log file: %w", err)
+		}
+	}
+	if c.buffer > 0 {
+		ring = newRingBuffer(c.buffer)
+	}
+	return file, ring, err
+}
+
+// RecentLogs trả về tối đa n dòng log gần nhất (cũ trước, mới sau) từ -log-buffer;
+// n <= 0 là toàn bộ buffer. Trả về nil khi buffer tắt hoặc dùng logger riêng (WithLogger).
+func (s *serviceCtx) RecentLogs(n int) []json.RawMessage {
+	if s.logRing == nil {
+		return nil
+	}
+	return s.logRing.recent(n)
+}
+
+// ringBuffer giữ n entry JSON gần nhất của logger.
+type ringBuffer struct {
+	mu      sync.Mutex
+	entries [][]byte
+	next    int
+	full    bool
+}
+
+func newRingBuffer(n int) *ringBuffer {
+	return &ringBuffer{entries: make([][]byte, n)}
+}
+
+// Write nhận đúng một entry mỗi lần gọi (cách zerolog ghi ra writer)
+func (r *ringBuffer) Write(p []byte) (int, error) {
+	entry := bytes.Clone(bytes.TrimRight(p, "\n"))
+	r.mu.Lock()
+	r.entries[r.next] = entry
+	r.next = (r.next + 1) % len(r.entries)
+	if r.next == 0 {
+		r.full = true
+	}
+	r.mu.Unlock()
+	return len(p), nil
+}
+
+func (r *ringBuffer) recent(n int) []json.RawMessage {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	size := r.next
+	if r.full {
+		size = len(r.entries)
+	}
+	if n <= 0 || n > size {
+		n = size
+	}
+	out := make([]json.RawMessage, n)
+	for i := range out {
+		idx := (r.next - n + i + len(r.entries)) % len(r.entries)
+		out[i] = json.RawMessage(r.entries[idx])
+	}
+	return out
+}
+
+// rotatingFile ghi log ra file, xoay file khi vượt maxSize hoặc sau rotateEvery.
+// File cũ được đổi tên thành <tên>-<thời điểm>[.n]<đuôi> và dọn theo maxBackups / maxAge
+// mỗi lần xoay và mỗi lần mở file.
+type rotatingFile struct {
+	mu          sync.Mutex
+	path        string
+	maxSize     int64
+	rotateEvery time.Duration
+	maxAge      time.Duration
+	maxBackups  int
+	now         func() time.Time
+
+	file     *os.File
+	size     int64
+	openedAt time.Time
+}
+
+const backupTimeFormat = "2006-01-02T15-04-05.000"
+
+func (f *rotatingFile) Write(p []byte) (int, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.file == nil {
+		if err := f.open(); err != nil {
+			return 0, err
+		}
+	}
+	tooBig := f.maxSize > 0 && f.size > 0 && f.size+int64(len(p)) > f.maxSize
+	tooOld := f.rotateEvery > 0 && f.now().Sub(f.openedAt) >= f.rotateEvery
+	if tooBig || tooOld {
+		if err := f.rotate(); err != nil {
+			return 0, err
+		}
+	}
+	n, err := f.file.Write(p)
+	f.size += int64(n)
+	return n, err
+}
+
+// Close đóng file; lần Write sau sẽ mở lại.
+func (f *rotatingFile) Close() error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.file == nil {
+		return nil
+	}
+	err := f.file.Close()
+	f.file = nil
+	return err
+}
+
+// open mở (hoặc tạo) file log rồi dọn file đã xoay, kể cả những file hết hạn từ lần chạy trước
+func (f *rotatingFile) open() error {
+	if err := os.MkdirAll(filepath.Dir(f.path), 0o755); err != nil {
+		return err
+	}
+	file, err := os.OpenFile(f.path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+	if err != nil {
+		return err
+	}
+	st, err := file.Stat()
+	if err != nil {
+		_ = file.Close()
+		return err
+	}
+	f.file, f.size, f.openedAt = file, st.Size(), f.now()
+	f.prune()
+	return nil
+}
+
+func (f *rotatingFile) rotate() error {
+	if err := f.file.Close(); err != nil {
+		return err
+	}
+	f.file = nil
+	backup, err := f.backupName()
+	if err != nil {
+		return err
+	}
+	if err := os.Rename(f.path, backup); err != nil {
+		return err
+	}
+	return f.open()
+}
+
+// backupName trả về tên file xoay chưa tồn tại: <tên>-<thời điểm><đuôi>, thêm .1, .2...
+// sau thời điểm khi nhiều lần xoay rơi vào cùng một mốc thời gian
+func (f *rotatingFile) backupName() (string, error) {
+	ext := filepath.Ext(f.path)
+	base := strings.TrimSuffix(f.path, ext) + "-" + f.now().Format(backupTimeFormat)
+	for seq := 0; ; seq++ {
+		name := base + ext
+		if seq > 0 {
+			name = base + "." + strconv.Itoa(seq) + ext
+		}
+		if _, err := os.Lstat(name); errors.Is(err, fs.ErrNotExist) {
+			return name, nil
+		} else if err != nil {
+			return "", err
+		}
+	}
+}
+
+// rotatedFile là một file đã xoay cùng thời điểm và số thứ tự đọc từ tên
+type rotatedFile struct {
+	path  string
+	stamp time.Time
+	seq   int
+}
+
+// backups liệt kê file do rotatingFile tạo, mới nhất trước
+func (f *rotatingFile) backups() []rotatedFile {
+	ext := filepath.Ext(f.path)
+	prefix := strings.TrimSuffix(f.path, ext) + "-"
+	matches, _ := filepath.Glob(prefix + "*" + ext)
+	var out []rotatedFile
+	for _, m := range matches {
+		rest := strings.TrimSuffix(strings.TrimPrefix(m, prefix), ext)
+		if len(rest) < len(backupTimeFormat) {
+			continue
+		}
+		stamp, err := time.Parse(backupTimeFormat, rest[:len(backupTimeFormat)])
+		if err != nil {
+			continue // không phải file do rotatingFile tạo
+		}
+		seq := 0
+		if suffix := rest[len(backupTimeFormat):]; suffix != "" {
+			n, err := strconv.Atoi(strings.TrimPrefix(suffix, "."))
+			if err != nil || n <= 0 || !strings.HasPrefix(suffix, ".") {
+				continue
+			}
+			seq = n
+		}
+		out = append(out, rotatedFile{path: m, stamp: stamp, seq: seq})
+	}
+	sort.Slice(out, func(i, j int) bool {
+		if !out[i].stamp.Equal(out[j].stamp) {
+			return out[i].stamp.After(out[j].stamp)
+		}
+		return out[i].seq > out[j].seq
+	})
+	return out
+}
+
+// prune xoá file đã xoay vượt quá maxBackups hoặc cũ hơn maxAge (lỗi xoá bị bỏ qua)
+func (f *rotatingFile) prune() {
+	kept := 0
+	for _, b := range f.backups() {
+		expired := false
+		if f.maxAge > 0 {
+			if st, err := os.Stat(b.path); err == nil && f.now().Sub(st.ModTime()) > f.maxAge {
+				expired = true
+			}
+		}
+		if expired || (f.maxBackups > 0 && kept >= f.maxBackups) {
+			_ = os.Remove(b.path)
+			continue
+		}
+		kept++
+	}
+}
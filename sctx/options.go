@@ -1,5 +1,10 @@
 package sctx
 
+import (
+	"strings"
+	"time"
+)
+
 type Option func(*serviceCtx)
 
 func WithName(name string) Option {
@@ -13,9 +18,75 @@ func WithComponent(c Component) Option {
 		}
 		s.components = append(s.components, c)
 		s.store[c.ID()] = c
+		s.states[c.ID()] = StateRegistered
 	}
 }
 
 func WithLogger(l Logger) Option {
 	return func(s *serviceCtx) { s.logger = l }
 }
+
+// WithParallelLifecycle bật chế độ activate/stop đồng thời các component không phụ thuộc nhau,
+// lần lượt theo từng tầng của đồ thị phụ thuộc.
+func WithParallelLifecycle() Option {
+	return func(s *serviceCtx) { s.parallel = true }
+}
+
+// WithActivateTimeout đặt deadline mặc định cho Activate của mỗi component (0 = không giới hạn).
+func WithActivateTimeout(d time.Duration) Option {
+	return func(s *serviceCtx) { s.activateTimeout = d }
+}
+
+// WithStopTimeout đặt deadline mặc định cho Stop của mỗi component (0 = không giới hạn).
+func WithStopTimeout(d time.Duration) Option {
+	return func(s *serviceCtx) { s.stopTimeout = d }
+}
+
+// WithComponentTimeouts ghi đè deadline Activate/Stop cho một component theo ID (0 = giữ mặc định).
+func WithComponentTimeouts(id string, activate, stop time.Duration) Option {
+	return func(s *serviceCtx) { s.timeouts[id] = componentTimeouts{activate: activate, stop: stop} }
+}
+
+// WithRestartSpec cấu hình giám sát Runnable cho một component theo ID (ưu tiên hơn Supervised).
+func WithRestartSpec(id string, spec RestartSpec) Option {
+	return func(s *serviceCtx) { s.restartSpecs[id] = spec }
+}
+
+// WithArgs đặt các arg command-line sẽ được parse (mặc định os.Args[1:]).
+func WithArgs(args []string) Option {
+	return func(s *serviceCtx) { s.args = args }
+}
+
+// WithEnvPrefix thêm prefix cho mọi tên ENV suy ra từ flag: WithEnvPrefix("svc") -> SVC_POSTGRES_URI.
+func WithEnvPrefix(prefix string) Option {
+	return func(s *serviceCtx) {
+		prefix = strings.ToUpper(strings.TrimSuffix(prefix, "_"))
+		if prefix != "" {
+			prefix += "_"
+		}
+		s.envPrefix = prefix
+	}
+}
+
+// WithComponentNamespaces đặt flag của mỗi component dưới namespace là ID của nó, để nhiều instance
+// của cùng plugin cùng tồn tại: NewPostgresDB("replica") -> -replica.postgres-uri / REPLICA_POSTGRES_URI.
+// Flag đã bắt đầu bằng ID (vd: -postgres-uri của component "postgres") giữ nguyên tên.
+func WithComponentNamespaces() Option {
+	return func(s *serviceCtx) { s.namespaces = true }
+}
+
+// WithFlagConstraints gắn constraint cho flag theo tên cuối cùng (sau namespace), kiểm tra trong Load.
+func WithFlagConstraints(name string, cs ...Constraint) Option {
+	return func(s *serviceCtx) { s.constraints[name] = append(s.constraints[name], cs...) }
+}
+
+// WithSecretSource thêm nguồn giá trị cho flag secret (vd: NewDirSecretSource("/run/secrets")).
+// Nằm trên config file, dưới .env và ENV; source thêm sau thắng.
+func WithSecretSource(src SecretSource) Option {
+	return func(s *serviceCtx) { s.secretSources = append(s.secretSources, src) }
+}
+
+// WithConfigLog ghi config hiệu lực (đã che secret) kèm nguồn của từng flag khi Load.
+func WithConfigLog() Option {
+	return func(s *serviceCtx) { s.logConfigOn = true }
+}
@@ -0,0 +1,61 @@
+package sctx
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+)
+
+// loadParallel activate từng tầng một, các component trong cùng tầng chạy đồng thời.
+// Nếu có lỗi, mọi component đã activate được dừng lại (ngược tầng) và lỗi được gộp.
+func (s *serviceCtx) loadParallel(ctx context.Context, levels [][]Component) error {
+	for _, level := range levels {
+		var err error
+		if err = ctx.Err(); err == nil {
+			err = errors.Join(runConcurrently(level, func(c Component) error { return s.activateComponent(ctx, c) })...)
+		}
+		if err != nil {
+			s.logger.Error("Activate failed: %v; rolling back", err)
+			_ = s.stopParallel(context.WithoutCancel(ctx), levels)
+			return err
+		}
+	}
+	return nil
+}
+
+// stopParallel dừng các tầng theo thứ tự ngược, component trong cùng tầng dừng đồng thời.
+func (s *serviceCtx) stopParallel(ctx context.Context, levels [][]Component) error {
+	var errs []error
+	for i := len(levels) - 1; i >= 0; i-- {
+		for _, err := range runConcurrently(levels[i], func(c Component) error { return s.stopComponent(ctx, c) }) {
+			s.logger.Error("Stop error: %v", err)
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// runConcurrently gọi fn cho từng component trên goroutine riêng và trả về các lỗi (đã gắn ID).
+func runConcurrently(cs []Component, fn func(c Component) error) []error {
+	errs := make([]error, len(cs))
+	var wg sync.WaitGroup
+	for i, c := range cs {
+		wg.Add(1)
+		go func(i int, c Component) {
+			defer wg.Done()
+			if err := fn(c); err != nil {
+				errs[i] = fmt.Errorf("%s: %w", c.ID(), err)
+			}
+		}(i, c)
+	}
+	wg.Wait()
+
+	out := errs[:0]
+	for _, err := range errs {
+		if err != nil {
+			out = append(out, err)
+		}
+	}
+	return out
+}
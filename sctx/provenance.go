@@ -0,0 +1,113 @@
+package sctx
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"text/tabwriter"
+)
+
+// ConfigSource cho biết tầng config nào quyết định giá trị hiện tại của một flag.
+type ConfigSource string
+
+const (
+	SourceDefault ConfigSource = "default"
+	SourceFile    ConfigSource = "file"   // config file (-config-file)
+	SourceSecret  ConfigSource = "secret" // SecretSource
+	SourceDotenv  ConfigSource = ".env"   // env file do sctx nạp
+	SourceEnv     ConfigSource = "env"    // biến môi trường thật (kể cả NAME_FILE)
+	SourceCLI     ConfigSource = "cli"    // command line
+)
+
+// layeredValue tính giá trị của flag từ các tầng dưới command line:
+// default < config file < secret source < .env < env.
+func (s *serviceCtx) layeredValue(f *flag.Flag, fileConfig, secrets, fileVars map[string]string,
+	lookup func(string) (string, bool)) (string, ConfigSource, error) {
+	value, src := f.DefValue, SourceDefault
+	if v, ok := fileConfig[f.Name]; ok {
+		value, src = v, SourceFile
+	}
+	if v, ok := secrets[f.Name]; ok {
+		value, src = v, SourceSecret
+	}
+	key := s.cmdLine.envNameFor(f.Name)
+	v, ok, err := lookupEnvFile(lookup, key)
+	if err != nil {
+		return "", "", err
+	}
+	if ok {
+		value, src = v, SourceEnv
+		if fv, fromFile := fileVars[key]; fromFile && fv == v {
+			if cur, set := os.LookupEnv(key); !set || s.fileEnv[key] == cur {
+				src = SourceDotenv
+			}
+		}
+	}
+	return value, src, nil
+}
+
+// recordSources ghi lại nguồn và giá trị của mọi flag sau khi parseFlags áp xong các tầng.
+func (s *serviceCtx) recordSources() error {
+	cli := s.cliFlags()
+	sources := make(map[string]ConfigSource)
+	values := make(map[string]string)
+	var err error
+	s.cmdLine.VisitAll(func(f *flag.Flag) {
+		values[f.Name] = f.Value.String()
+		if cli[f.Name] {
+			sources[f.Name] = SourceCLI
+			return
+		}
+		_, src, e := s.layeredValue(f, s.fileConfig, s.sourceSecrets, s.fileEnv, os.LookupEnv)
+		if e != nil && err == nil {
+			err = e
+		}
+		sources[f.Name] = src
+	})
+	s.sources = sources
+	s.flagValues = values
+	return err
+}
+
+// cliFlags trả về các flag được set trong phần args đã parse (trước subcommand).
+func (s *serviceCtx) cliFlags() map[string]bool {
+	out := make(map[string]bool)
+	consumed := s.args[:len(s.args)-len(s.cmdLine.Args())]
+	for _, arg := range consumed {
+		if arg == "--" || !strings.HasPrefix(arg, "-") {
+			continue
+		}
+		name := strings.TrimPrefix(strings.TrimPrefix(arg, "-"), "-")
+		name, _, _ = strings.Cut(name, "=")
+		if s.cmdLine.Lookup(name) != nil {
+			out[name] = true
+		}
+	}
+	return out
+}
+
+func (s *serviceCtx) sourceOf(name string) ConfigSource {
+	if src, ok := s.sources[name]; ok {
+		return src
+	}
+	return SourceDefault
+}
+
+// logConfig ghi config hiệu lực (đã che secret) kèm nguồn, bật bằng WithConfigLog.
+func (s *serviceCtx) logConfig() {
+	for _, e := range s.Config() {
+		s.logger.Info("Config -%s = %q (%s)", e.Name, e.Value, e.Source)
+	}
+}
+
+// writeConfigTable in config dạng bảng cho `config print -format table`
+func writeConfigTable(w io.Writer, entries []ConfigEntry) error {
+	tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+	_, _ = fmt.Fprintln(tw, "FLAG\tENV\tVALUE\tSOURCE")
+	for _, e := range entries {
+		_, _ = fmt.Fprintf(tw, "-%s\t%s\t%s\t%s\n", e.Name, e.Env, e.Value, e.Source)
+	}
+	return tw.Flush()
+}
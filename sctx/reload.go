@@ -0,0 +1,220 @@
+package sctx
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+)
+
+// Reloadable là interface tuỳ chọn: component nhận giá trị mới (dạng chuỗi như trên command line)
+// của các flag nó sở hữu đã thay đổi khi reload, theo tên flag nó đã đăng ký
+// (không có tiền tố của WithComponentNamespaces).
+// sctx không ghi vào biến gắn với flag: component tự parse và áp dụng dưới lock của nó.
+// Trả lỗi để huỷ toàn bộ lần reload.
+type Reloadable interface {
+	Reload(ctx context.Context, changed map[string]string) error
+}
+
+// flagChange là một flag đổi giá trị sau khi đọc lại config.
+type flagChange struct {
+	name     string // tên trên FlagSet của service
+	local    string // tên component đã đăng ký
+	old, new string
+}
+
+// Reload đọc lại config file, SecretSource, các env file đã nạp lúc khởi động + biến môi trường (kể cả NAME_FILE), tính các flag thay đổi và gọi Reload
+// trên component sở hữu chúng. Nếu bất kỳ bước nào lỗi, config cũ được giữ nguyên.
+// Flag của component không Reloadable (hoặc không active) bị bỏ qua kèm cảnh báo.
+// Component được gọi ngoài lifeMu nên có thể gọi lại ServiceContext.
+func (s *serviceCtx) Reload(ctx context.Context) error {
+	s.reloadMu.Lock()
+	defer s.reloadMu.Unlock()
+
+	fileVars, _, err := readEnvFiles(s.envFiles, true)
+	if err != nil {
+		return fmt.Errorf("sctx: reload: %w", err)
+	}
+	lookup := s.envLookup(fileVars)
+
+	secrets, err := s.secretValues()
+	if err != nil {
+		return fmt.Errorf("sctx: reload: %w", err)
+	}
+
+	fileConfig := s.fileConfig
+	if s.configFilePath != "" {
+		values, _, err := readConfigFile(s.cmdLine, s.configFilePath)
+		if err != nil {
+			return fmt.Errorf("sctx: reload: %w", err)
+		}
+		fileConfig = values
+	}
+
+	plan, sources, err := s.planReload(fileConfig, secrets, fileVars, lookup)
+	if err != nil {
+		return err
+	}
+
+	var reloaded []Component
+	for _, c := range plan.components {
+		if err := c.(Reloadable).Reload(ctx, plan.values(c.ID(), false)); err != nil {
+			s.logger.Error("Reload %s failed: %v; restoring previous config", c.ID(), err)
+			for i := len(reloaded) - 1; i >= 0; i-- {
+				_ = reloaded[i].(Reloadable).Reload(ctx, plan.values(reloaded[i].ID(), true))
+			}
+			return fmt.Errorf("sctx: reload %s: %w", c.ID(), err)
+		}
+		reloaded = append(reloaded, c)
+	}
+
+	s.cfgMu.Lock()
+	defer s.cfgMu.Unlock()
+	for owner, changes := range plan.byOwner {
+		for _, ch := range changes {
+			if owner == "" {
+				// flag của service (-log-level): sctx tự giữ biến, ghi dưới cfgMu
+				_ = setFlagValue(s.cmdLine.FlagSet, s.cmdLine.Lookup(ch.name), ch.new)
+			}
+			s.flagValues[ch.name] = ch.new
+		}
+	}
+	s.syncFileEnv(fileVars)
+	s.fileConfig = fileConfig
+	s.sourceSecrets = secrets
+	for name, src := range sources {
+		s.sources[name] = src
+	}
+	if len(plan.byOwner[""]) > 0 {
+		_ = s.logLevels.set(s.logLevel) // đã kiểm tra trong planReload
+	}
+	s.logger.Info("Config reloaded: %d flag(s) changed", plan.count)
+	return nil
+}
+
+// reloadPlan: các flag đổi giá trị theo component sở hữu ("" = service), theo thứ tự khởi động.
+type reloadPlan struct {
+	byOwner    map[string][]flagChange
+	components []Component
+	count      int
+}
+
+// values trả về giá trị mới của flag thuộc owner (hoặc giá trị cũ khi hoàn tác).
+func (p *reloadPlan) values(owner string, revert bool) map[string]string {
+	out := make(map[string]string, len(p.byOwner[owner]))
+	for _, ch := range p.byOwner[owner] {
+		if revert {
+			out[ch.local] = ch.old
+		} else {
+			out[ch.local] = ch.new
+		}
+	}
+	return out
+}
+
+// planReload tính giá trị mới của từng flag dưới lifeMu (đọc component và trạng thái của chúng),
+// không ghi gì vào flag.
+func (s *serviceCtx) planReload(fileConfig, secrets, fileVars map[string]string, lookup func(string) (string, bool)) (*reloadPlan, map[string]ConfigSource, error) {
+	s.lifeMu.Lock()
+	defer s.lifeMu.Unlock()
+	s.cfgMu.RLock()
+	defer s.cfgMu.RUnlock()
+
+	plan := &reloadPlan{byOwner: make(map[string][]flagChange)}
+	sources := make(map[string]ConfigSource)
+	var planErr error
+	s.cmdLine.VisitAll(func(f *flag.Flag) {
+		// command line là tầng trên cùng, không đổi khi chạy
+		if planErr != nil || s.sourceOf(f.Name) == SourceCLI {
+			return
+		}
+		next, src, err := s.layeredValue(f, fileConfig, secrets, fileVars, lookup)
+		if err != nil {
+			planErr = err
+			return
+		}
+		old := s.flagValue(f)
+		if next == old {
+			sources[f.Name] = src
+			return
+		}
+
+		// -log-level thuộc service và luôn reload được
+		owner := s.flagOwners[f.Name]
+		if f.Name == "log-level" {
+			if err := validLogLevel(next); err != nil {
+				planErr = fmt.Errorf("sctx: reload flag %s: %w", f.Name, err)
+				return
+			}
+		} else if _, ok := s.store[owner].(Reloadable); !ok || s.stateOf(owner) != StateActive {
+			if owner == "" {
+				owner = s.name
+			}
+			s.logger.Warn("Flag %s changed (%s -> %s) but %s cannot reload it; restart required",
+				f.Name, s.cmdLine.redact(f, old), s.cmdLine.redact(f, next), owner)
+			return
+		}
+		sources[f.Name] = src
+		local := f.Name
+		if n, ok := s.flagLocal[f.Name]; ok {
+			local = n
+		}
+		plan.byOwner[owner] = append(plan.byOwner[owner], flagChange{name: f.Name, local: local, old: old, new: next})
+		plan.count++
+	})
+	if planErr != nil {
+		return nil, nil, planErr
+	}
+	for _, c := range s.order {
+		if len(plan.byOwner[c.ID()]) > 0 {
+			plan.components = append(plan.components, c)
+		}
+	}
+	return plan, sources, nil
+}
+
+// flagValue: giá trị hiện hành của flag, lấy từ bản chụp lúc parse + các lần reload
+// vì biến của flag component do component giữ (gọi khi đang giữ cfgMu).
+func (s *serviceCtx) flagValue(f *flag.Flag) string {
+	if v, ok := s.flagValues[f.Name]; ok {
+		return v
+	}
+	return f.Value.String()
+}
+
+// envLookup: biến môi trường thật thắng env file; biến do sctx nạp từ file trước đó
+// (và chưa bị ai đổi) được thay bằng giá trị mới trong file.
+func (s *serviceCtx) envLookup(fileVars map[string]string) func(string) (string, bool) {
+	return func(key string) (string, bool) {
+		if cur, ok := os.LookupEnv(key); ok {
+			if loaded, fromFile := s.fileEnv[key]; !fromFile || loaded != cur {
+				return cur, true
+			}
+		}
+		v, ok := fileVars[key]
+		return v, ok
+	}
+}
+
+// syncFileEnv cập nhật os env theo nội dung env file mới, chỉ với các biến do sctx nạp.
+func (s *serviceCtx) syncFileEnv(fileVars map[string]string) {
+	next := make(map[string]string)
+	for key, loaded := range s.fileEnv {
+		if cur, ok := os.LookupEnv(key); ok && cur != loaded {
+			continue
+		}
+		if _, ok := fileVars[key]; !ok {
+			_ = os.Unsetenv(key)
+		}
+	}
+	for key, v := range fileVars {
+		if cur, ok := os.LookupEnv(key); ok {
+			if loaded, fromFile := s.fileEnv[key]; !fromFile || loaded != cur {
+				continue
+			}
+		}
+		_ = os.Setenv(key, v)
+		next[key] = v
+	}
+	s.fileEnv = next
+}
@@ -2,27 +2,280 @@ package sctx
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"os"
 	"os/signal"
+	"strings"
 	"syscall"
+	"time"
 )
 
-func Run(app ServiceContext, fn func(ctx context.Context) error) (err error) {
-	ctx, cancel := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
-	defer cancel()
+const (
+	DefaultGracePeriod = 30 * time.Second
+	ForcedExitCode     = 1
+)
+
+var ErrShutdownTimeout = errors.New("sctx: shutdown grace period exceeded")
+
+// exitFunc cho phép test thay thế os.Exit khi nhận signal lần hai
+var exitFunc = os.Exit
+
+type RunOption func(*runConfig)
+
+type namedApp struct {
+	name string
+	fn   func(ctx context.Context) error
+}
+
+type runConfig struct {
+	apps     []namedApp
+	grace    time.Duration
+	commands []command
+}
+
+// WithApp thêm một app function chạy đồng thời với các app khác trong RunApps.
+func WithApp(name string, fn func(ctx context.Context) error) RunOption {
+	return func(c *runConfig) { c.apps = append(c.apps, namedApp{name: name, fn: fn}) }
+}
+
+// WithGracePeriod giới hạn tổng thời gian shutdown: chờ các app thoát + Stop component (0 = không giới hạn).
+func WithGracePeriod(d time.Duration) RunOption {
+	return func(c *runConfig) { c.grace = d }
+}
+
+type PartReport struct {
+	Name     string
+	Err      error
+	TimedOut bool
+	Duration time.Duration
+}
+
+type RunReport struct {
+	Parts   []PartReport
+	Fatal   error // lỗi escalate từ runnable critical
+	StopErr error
+}
+
+// RunError liệt kê các phần bị lỗi hoặc quá hạn trong một lần RunApps.
+type RunError struct {
+	Report *RunReport
+	errs   []error
+}
+
+func (e *RunError) Error() string {
+	msgs := make([]string, 0, len(e.errs))
+	for _, err := range e.errs {
+		msgs = append(msgs, err.Error())
+	}
+	return "sctx: run failed: " + strings.Join(msgs, "; ")
+}
+
+func (e *RunError) Unwrap() []error { return e.errs }
+
+// Err trả về *RunError nếu có phần nào lỗi, quá hạn, hoặc Stop lỗi; nil nếu tất cả ổn.
+// context.Canceled trả về sau khi shutdown bắt đầu không tính là lỗi.
+func (r *RunReport) Err() error {
+	var errs []error
+	for _, p := range r.Parts {
+		switch {
+		case p.TimedOut:
+			errs = append(errs, fmt.Errorf("%s: %w", p.Name, ErrShutdownTimeout))
+		case p.Err != nil && !errors.Is(p.Err, context.Canceled):
+			errs = append(errs, fmt.Errorf("%s: %w", p.Name, p.Err))
+		}
+	}
+	if r.Fatal != nil {
+		errs = append(errs, r.Fatal)
+	}
+	if r.StopErr != nil {
+		errs = append(errs, fmt.Errorf("stop: %w", r.StopErr))
+	}
+	if len(errs) == 0 {
+		return nil
+	}
+	return &RunError{Report: r, errs: errs}
+}
 
-	if err = app.Load(); err != nil {
+// Run là RunApps với một app duy nhất; trả về nguyên lỗi của fn.
+func Run(app ServiceContext, fn func(ctx context.Context) error) error {
+	rep, err := runApps(app, &runConfig{
+		apps:  []namedApp{{name: "main", fn: fn}},
+		grace: DefaultGracePeriod,
+	})
+	if err != nil {
 		return err
 	}
+	if p := rep.Parts[0]; p.Err != nil {
+		return p.Err
+	} else if p.TimedOut {
+		return ErrShutdownTimeout
+	}
+	return rep.Fatal
+}
+
+// RunApps load service, chạy các app function đồng thời (kiểu errgroup) rồi shutdown:
+//   - lỗi đầu tiên (hoặc SIGINT/SIGTERM, hoặc runnable critical bỏ cuộc) huỷ ctx của mọi app
+//   - sau đó các app và Stop component có tổng cộng grace period để hoàn tất
+//   - signal lần hai thoát ngay với mã ForcedExitCode
+//   - SIGHUP gọi app.Reload
+func RunApps(app ServiceContext, opts ...RunOption) error {
+	cfg := &runConfig{grace: DefaultGracePeriod}
+	for _, o := range opts {
+		o(cfg)
+	}
+	rep, err := runApps(app, cfg)
+	if err != nil {
+		return err
+	}
+	return rep.Err()
+}
 
-	defer func() {
-		_ = app.Stop()
+func runApps(app ServiceContext, cfg *runConfig) (*RunReport, error) {
+	// file log đóng sau dòng log cuối (báo cáo shutdown), không chỉ trong StopContext
+	defer closeLogSinks(app)
+	log := app.Logger("run")
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	sigCh := make(chan os.Signal, 2)
+	signal.Notify(sigCh, syscall.SIGINT, syscall.SIGTERM)
+	defer signal.Stop(sigCh)
+
+	hupCh := make(chan os.Signal, 1)
+	signal.Notify(hupCh, syscall.SIGHUP)
+	defer signal.Stop(hupCh)
+
+	finished := make(chan struct{})
+	defer close(finished)
+	go func() {
+		for {
+			select {
+			case <-hupCh:
+				log.Info("Received SIGHUP, reloading config")
+				if err := app.Reload(ctx); err != nil {
+					log.Error("Reload failed: %v", err)
+				}
+			case <-finished:
+				return
+			}
+		}
+	}()
+	go func() {
+		select {
+		case sig := <-sigCh:
+			log.Info("Received %s, shutting down (send again to force exit)", sig)
+			cancel()
+		case <-app.Done():
+			cancel()
+		case <-finished:
+			return
+		}
+		select {
+		case sig := <-sigCh:
+			log.Error("Received %s again, forcing exit", sig)
+			exitFunc(ForcedExitCode)
+		case <-finished:
+		}
 	}()
 
-	if e := fn(ctx); e != nil {
-		err = e
+	// Signal trong lúc khởi động sẽ huỷ activation thay vì chờ nó xong
+	if err := app.LoadContext(ctx); err != nil {
+		if e := app.Err(); e != nil {
+			return nil, e
+		}
+		return nil, err
 	}
 
-	signal.Stop(make(chan os.Signal, 1))
-	return
+	rep := &RunReport{Parts: make([]PartReport, len(cfg.apps))}
+	groupCtx, groupCancel := context.WithCancel(ctx)
+	defer groupCancel()
+
+	type result struct {
+		idx int
+		err error
+		dur time.Duration
+	}
+	results := make(chan result, len(cfg.apps))
+	for i, a := range cfg.apps {
+		rep.Parts[i] = PartReport{Name: a.name}
+		go func(i int, fn func(context.Context) error) {
+			start := time.Now()
+			err := fn(groupCtx)
+			results <- result{idx: i, err: err, dur: time.Since(start)}
+		}(i, a.fn)
+	}
+
+	if len(cfg.apps) == 0 {
+		// không có app: chạy component tới khi nhận signal hoặc service tự dừng (app.Done)
+		<-ctx.Done()
+	}
+
+	done := make([]bool, len(cfg.apps))
+	var deadline <-chan time.Time
+	var stopCtx context.Context = context.WithoutCancel(ctx)
+	shutdown := groupCtx.Done()
+	for pending := len(cfg.apps); pending > 0; {
+		select {
+		case r := <-results:
+			pending--
+			done[r.idx] = true
+			rep.Parts[r.idx].Err, rep.Parts[r.idx].Duration = r.err, r.dur
+			if r.err != nil && groupCtx.Err() == nil {
+				log.Error("App %s failed: %v; shutting down", rep.Parts[r.idx].Name, r.err)
+				groupCancel()
+			}
+		case <-shutdown:
+			shutdown = nil
+			if cfg.grace > 0 {
+				var stopCancel context.CancelFunc
+				stopCtx, stopCancel = context.WithTimeout(stopCtx, cfg.grace)
+				defer stopCancel()
+				deadline = time.After(cfg.grace)
+			}
+		case <-deadline:
+			for i := range rep.Parts {
+				if !done[i] {
+					rep.Parts[i].TimedOut = true
+				}
+			}
+			pending = 0
+		}
+	}
+	if shutdown != nil && cfg.grace > 0 {
+		var stopCancel context.CancelFunc
+		stopCtx, stopCancel = context.WithTimeout(stopCtx, cfg.grace)
+		defer stopCancel()
+	}
+
+	rep.StopErr = app.StopContext(stopCtx)
+	rep.Fatal = app.Err()
+	logReport(log, rep)
+	return rep, nil
+}
+
+// closeLogSinks đóng file log của app khi runApps/Execute kết thúc
+func closeLogSinks(app ServiceContext) {
+	if s, ok := app.(interface{ closeLogSinks() }); ok {
+		s.closeLogSinks()
+	}
+}
+
+func logReport(log Logger, rep *RunReport) {
+	for _, p := range rep.Parts {
+		switch {
+		case p.TimedOut:
+			log.Error("App %s: timed out during shutdown", p.Name)
+		case p.Err != nil && !errors.Is(p.Err, context.Canceled):
+			log.Error("App %s: failed after %s: %v", p.Name, p.Duration, p.Err)
+		default:
+			log.Info("App %s: exited after %s", p.Name, p.Duration)
+		}
+	}
+	if rep.Fatal != nil {
+		log.Error("Service escalated: %v", rep.Fatal)
+	}
+	if rep.StopErr != nil {
+		log.Error("Stop: %v", rep.StopErr)
+	}
 }
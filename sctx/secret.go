@@ -0,0 +1,103 @@
+package sctx
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// SecretSource cung cấp giá trị cho các flag secret từ nơi khác ngoài env/config file
+// (thư mục secret mount vào container, Vault...). name là tên flag sau namespace.
+type SecretSource interface {
+	Lookup(name string) (value string, ok bool, err error)
+}
+
+// DirSecretSource đọc secret từ file trong một thư mục (Docker/Kubernetes secrets):
+// với flag -postgres-uri, thử lần lượt <dir>/postgres-uri rồi <dir>/POSTGRES_URI.
+type DirSecretSource struct {
+	Dir string
+}
+
+func NewDirSecretSource(dir string) *DirSecretSource {
+	return &DirSecretSource{Dir: dir}
+}
+
+func (d *DirSecretSource) Lookup(name string) (string, bool, error) {
+	for _, file := range []string{name, configKey(name)} {
+		v, err := readSecretFile(filepath.Join(d.Dir, file))
+		if errors.Is(err, os.ErrNotExist) {
+			continue
+		}
+		if err != nil {
+			return "", false, err
+		}
+		return v, true, nil
+	}
+	return "", false, nil
+}
+
+// MarkSecret đánh dấu flag name là secret (gọi trong InitFlags): giá trị bị che trong usage,
+// config dump, log, và được tra trong các SecretSource.
+func MarkSecret(name string) {
+	MarkSecretFlagSet(flag.CommandLine, name)
+}
+
+// MarkSecretFlagSet giống MarkSecret nhưng cho flag trên fs (dùng trong RegisterFlags).
+// Chỉ có hiệu lực với FlagSet sctx truyền vào RegisterFlags/InitFlags.
+func MarkSecretFlagSet(fs *flag.FlagSet, name string) {
+	if a := appFlagSetOf(fs); a != nil {
+		a.MarkSecret(name)
+	}
+}
+
+// secretValues tra mọi flag secret trong các SecretSource; source đăng ký sau thắng.
+func (s *serviceCtx) secretValues() (map[string]string, error) {
+	values := make(map[string]string)
+	if len(s.secretSources) == 0 {
+		return values, nil
+	}
+	var errs []error
+	s.cmdLine.VisitAll(func(f *flag.Flag) {
+		if !s.cmdLine.isSecret(f) {
+			return
+		}
+		for _, src := range s.secretSources {
+			v, ok, err := src.Lookup(f.Name)
+			if err != nil {
+				errs = append(errs, fmt.Errorf("sctx: secret for -%s: %w", f.Name, err))
+				continue
+			}
+			if ok {
+				values[f.Name] = v
+			}
+		}
+	})
+	return values, errors.Join(errs...)
+}
+
+// loadSecrets áp giá trị từ SecretSource vào flag (trên config file, dưới .env và ENV).
+func (s *serviceCtx) loadSecrets() error {
+	values, err := s.secretValues()
+	if err != nil {
+		return err
+	}
+	for name, v := range values {
+		if err := setFlagValue(s.cmdLine.FlagSet, s.cmdLine.Lookup(name), v); err != nil {
+			return fmt.Errorf("sctx: secret for -%s: %w", name, err)
+		}
+	}
+	s.sourceSecrets = values
+	return nil
+}
+
+// readSecretFile đọc file secret, bỏ xuống dòng ở cuối (echo "x" > file)
+func readSecretFile(path string) (string, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimRight(string(b), "\r\n"), nil
+}
@@ -0,0 +1,222 @@
+package sctx
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"runtime"
+	"time"
+
+	"github.com/rs/zerolog"
+)
+
+// SlogHandler là slog.Handler ghi qua một sctx.Logger, để thư viện nhận *slog.Logger
+// log ra cùng chỗ và mang theo field service/prefix:
+//
+//	slog.New(sctx.NewSlogHandler(sv.Logger("redis")))
+//
+// Attr trong group được ghi thành object lồng nhau.
+type SlogHandler struct {
+	log    Logger
+	groups []string
+	scoped []scopedAttrs // attrs thêm sau WithGroup, nằm trong groups[:depth]
+}
+
+type scopedAttrs struct {
+	depth int
+	attrs []slog.Attr
+}
+
+func NewSlogHandler(l Logger) *SlogHandler {
+	return &SlogHandler{log: l}
+}
+
+// Enabled hỏi Logger nếu nó biết level của mình (ZeroLogger), ngược lại nhận mọi record.
+func (h *SlogHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	if e, ok := h.log.(interface {
+		Enabled(context.Context, slog.Level) bool
+	}); ok {
+		return e.Enabled(ctx, level)
+	}
+	return true
+}
+
+// Handle gắn thêm field của ctx (ContextWithFields), vd với slog.InfoContext.
+func (h *SlogHandler) Handle(ctx context.Context, r slog.Record) error {
+	attrs := make([]slog.Attr, 0, r.NumAttrs())
+	r.Attrs(func(a slog.Attr) bool {
+		attrs = append(attrs, a)
+		return true
+	})
+	// dựng lại group từ trong ra ngoài
+	for d := len(h.groups); d > 0; d-- {
+		var outer []slog.Attr
+		for _, s := range h.scoped {
+			if s.depth == d {
+				outer = append(outer, s.attrs...)
+			}
+		}
+		attrs = append(outer, attrs...)
+		if len(attrs) > 0 {
+			attrs = []slog.Attr{{Key: h.groups[d-1], Value: slog.GroupValue(attrs...)}}
+		}
+	}
+
+	kv := attrsToKV(attrs)
+	log := h.log.Ctx(ctx)
+	switch {
+	case r.Level < slog.LevelInfo:
+		log.Debugw(r.Message, kv...)
+	case r.Level < slog.LevelWarn:
+		log.Infow(r.Message, kv...)
+	case r.Level < slog.LevelError:
+		log.Warnw(r.Message, kv...)
+	default:
+		log.Errorw(r.Message, kv...)
+	}
+	return nil
+}
+
+func (h *SlogHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	if len(attrs) == 0 {
+		return h
+	}
+	h2 := *h
+	if len(h.groups) == 0 {
+		h2.log = h.log.With(attrsToKV(attrs)...)
+		return &h2
+	}
+	h2.scoped = append(h.scoped[:len(h.scoped):len(h.scoped)], scopedAttrs{depth: len(h.groups), attrs: attrs})
+	return &h2
+}
+
+func (h *SlogHandler) WithGroup(name string) slog.Handler {
+	if name == "" {
+		return h
+	}
+	h2 := *h
+	h2.groups = append(h.groups[:len(h.groups):len(h.groups)], name)
+	return &h2
+}
+
+// attrsToKV đổi attr sang cặp key-value của Logger; group thành map lồng nhau,
+// group không có key được trải phẳng và attr rỗng bị bỏ qua (theo quy ước của slog).
+func attrsToKV(attrs []slog.Attr) []any {
+	kv := make([]any, 0, 2*len(attrs))
+	for _, a := range attrs {
+		a.Value = a.Value.Resolve()
+		if a.Equal(slog.Attr{}) {
+			continue
+		}
+		if a.Value.Kind() == slog.KindGroup {
+			group := a.Value.Group()
+			if len(group) == 0 {
+				continue
+			}
+			if a.Key == "" {
+				kv = append(kv, attrsToKV(group)...)
+				continue
+			}
+			m := make(map[string]any, len(group))
+			sub := attrsToKV(group)
+			for i := 0; i+1 < len(sub); i += 2 {
+				m[sub[i].(string)] = sub[i+1]
+			}
+			kv = append(kv, a.Key, m)
+			continue
+		}
+		kv = append(kv, a.Key, a.Value.Any())
+	}
+	return kv
+}
+
+// SlogLogger là sctx.Logger ghi qua một slog.Handler bất kỳ, dùng với WithLogger:
+//
+//	sctx.New(sctx.WithLogger(sctx.NewSlogLogger(slog.NewJSONHandler(os.Stdout, nil))))
+//
+// WithPrefix thêm attr "prefix"; level do handler quyết định.
+type SlogLogger struct {
+	handler slog.Handler
+}
+
+func NewSlogLogger(h slog.Handler) *SlogLogger {
+	return &SlogLogger{handler: h}
+}
+
+// Handler trả về slog.Handler bên dưới (đã kèm các field của With/WithPrefix).
+func (l *SlogLogger) Handler() slog.Handler { return l.handler }
+
+func (l *SlogLogger) Debug(msg string, args ...any) { l.logf(slog.LevelDebug, msg, args) }
+func (l *SlogLogger) Info(msg string, args ...any)  { l.logf(slog.LevelInfo, msg, args) }
+func (l *SlogLogger) Warn(msg string, args ...any)  { l.logf(slog.LevelWarn, msg, args) }
+func (l *SlogLogger) Error(msg string, args ...any) { l.logf(slog.LevelError, msg, args) }
+
+func (l *SlogLogger) Debugw(msg string, kv ...any) { l.logw(slog.LevelDebug, msg, kv) }
+func (l *SlogLogger) Infow(msg string, kv ...any)  { l.logw(slog.LevelInfo, msg, kv) }
+func (l *SlogLogger) Warnw(msg string, kv ...any)  { l.logw(slog.LevelWarn, msg, kv) }
+func (l *SlogLogger) Errorw(msg string, kv ...any) { l.logw(slog.LevelError, msg, kv) }
+
+func (l *SlogLogger) With(kv ...any) Logger {
+	var r slog.Record
+	r.Add(kv...)
+	attrs := make([]slog.Attr, 0, r.NumAttrs())
+	r.Attrs(func(a slog.Attr) bool {
+		attrs = append(attrs, a)
+		return true
+	})
+	return &SlogLogger{handler: l.handler.WithAttrs(attrs)}
+}
+
+func (l *SlogLogger) Ctx(ctx context.Context) Logger {
+	if fields := FieldsFromContext(ctx); len(fields) > 0 {
+		return l.With(fields...)
+	}
+	return l
+}
+
+func (l *SlogLogger) WithPrefix(prefix string) Logger {
+	return &SlogLogger{handler: l.handler.WithAttrs([]slog.Attr{slog.String("prefix", prefix)})}
+}
+
+func (l *SlogLogger) Enabled(ctx context.Context, level slog.Level) bool {
+	return l.handler.Enabled(ctx, level)
+}
+
+func (l *SlogLogger) logf(level slog.Level, msg string, args []any) {
+	if !l.handler.Enabled(context.Background(), level) {
+		return
+	}
+	if len(args) > 0 {
+		msg = fmt.Sprintf(msg, args...)
+	}
+	l.write(level, msg, nil)
+}
+
+func (l *SlogLogger) logw(level slog.Level, msg string, kv []any) {
+	if !l.handler.Enabled(context.Background(), level) {
+		return
+	}
+	l.write(level, msg, kv)
+}
+
+func (l *SlogLogger) write(level slog.Level, msg string, kv []any) {
+	var pcs [1]uintptr
+	runtime.Callers(4, pcs[:]) // bỏ qua Callers, write, logf/logw, Info...
+	r := slog.NewRecord(time.Now(), level, msg, pcs[0])
+	r.Add(kv...)
+	_ = l.handler.Handle(context.Background(), r)
+}
+
+// Enabled cho SlogHandler biết level nào ZeroLogger đang ghi (theo -log-level và prefix).
+func (l *ZeroLogger) Enabled(_ context.Context, level slog.Level) bool {
+	lvl := zerolog.ErrorLevel
+	switch {
+	case level < slog.LevelInfo:
+		lvl = zerolog.DebugLevel
+	case level < slog.LevelWarn:
+		lvl = zerolog.InfoLevel
+	case level < slog.LevelError:
+		lvl = zerolog.WarnLevel
+	}
+	return l.levels.enabled(l.prefix, lvl)
+}
@@ -0,0 +1,211 @@
+package sctx
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+var (
+	ErrRunnablePanic = errors.New("sctx: runnable panicked")
+	ErrRestartLimit  = errors.New("sctx: restart limit reached")
+)
+
+// Runnable là interface tuỳ chọn cho component có vòng lặp nền (consumer loop, worker pool...).
+// Sau khi Activate thành công, sctx gọi Run trên goroutine riêng và giám sát nó;
+// ctx bị huỷ khi component bị Stop.
+type Runnable interface {
+	Run(ctx context.Context) error
+}
+
+type RestartPolicy int
+
+const (
+	RestartOnFailure RestartPolicy = iota // restart khi Run trả lỗi hoặc panic
+	RestartNever                          // không bao giờ restart
+	RestartAlways                         // restart cả khi Run trả nil
+)
+
+func (p RestartPolicy) String() string {
+	return [...]string{"on-failure", "never", "always"}[p]
+}
+
+// RestartSpec cấu hình việc giám sát một Runnable. Giá trị 0 dùng mặc định.
+type RestartSpec struct {
+	Policy      RestartPolicy
+	MinBackoff  time.Duration // mặc định 100ms, nhân đôi sau mỗi lần restart
+	MaxBackoff  time.Duration // mặc định 30s
+	MaxRestarts int           // mặc định 5 lần trong Window
+	Window      time.Duration // mặc định 1 phút
+	Critical    bool          // hết lượt restart -> shutdown toàn bộ service
+}
+
+// Supervised là interface tuỳ chọn để Runnable tự khai báo RestartSpec.
+type Supervised interface {
+	RestartSpec() RestartSpec
+}
+
+func (r RestartSpec) withDefaults() RestartSpec {
+	if r.MinBackoff <= 0 {
+		r.MinBackoff = 100 * time.Millisecond
+	}
+	if r.MaxBackoff <= 0 {
+		r.MaxBackoff = 30 * time.Second
+	}
+	if r.MaxBackoff < r.MinBackoff {
+		r.MaxBackoff = r.MinBackoff
+	}
+	if r.MaxRestarts <= 0 {
+		r.MaxRestarts = 5
+	}
+	if r.Window <= 0 {
+		r.Window = time.Minute
+	}
+	return r
+}
+
+type runner struct {
+	cancel context.CancelFunc
+	done   chan struct{}
+}
+
+func (s *serviceCtx) restartSpecFor(c Component) RestartSpec {
+	if spec, ok := s.restartSpecs[c.ID()]; ok {
+		return spec.withDefaults()
+	}
+	if sv, ok := c.(Supervised); ok {
+		return sv.RestartSpec().withDefaults()
+	}
+	return RestartSpec{}.withDefaults()
+}
+
+// startRunnable chạy Run của component (nếu có) dưới sự giám sát của sctx.
+func (s *serviceCtx) startRunnable(c Component) {
+	r, ok := c.(Runnable)
+	if !ok {
+		return
+	}
+	ctx, cancel := context.WithCancel(s.runCtx)
+	rn := &runner{cancel: cancel, done: make(chan struct{})}
+
+	s.stateMu.Lock()
+	s.runners[c.ID()] = rn
+	s.stateMu.Unlock()
+
+	go s.supervise(ctx, c, r, s.restartSpecFor(c), rn.done)
+}
+
+// haltRunnable huỷ Run của component và chờ nó thoát (tối đa tới khi ctx hết hạn).
+func (s *serviceCtx) haltRunnable(ctx context.Context, id string) {
+	s.stateMu.Lock()
+	rn, ok := s.runners[id]
+	delete(s.runners, id)
+	s.stateMu.Unlock()
+	if !ok {
+		return
+	}
+
+	rn.cancel()
+	select {
+	case <-rn.done:
+	case <-ctx.Done():
+		s.logger.Warn("Runnable %s did not exit before stop deadline", id)
+	}
+}
+
+func (s *serviceCtx) supervise(ctx context.Context, c Component, r Runnable, spec RestartSpec, done chan struct{}) {
+	err := s.superviseLoop(ctx, c, r, spec)
+	close(done)
+	if err == nil {
+		return
+	}
+
+	if spec.Critical {
+		s.logger.Error("Critical runnable %s gave up: %v; shutting down service", c.ID(), err)
+		s.escalate(fmt.Errorf("%s: %w", c.ID(), err))
+		return
+	}
+	s.logger.Error("Runnable %s gave up: %v", c.ID(), err)
+	_ = s.stopComponent(context.WithoutCancel(ctx), c)
+	s.transition(c.ID(), StateStopped, StateFailed)
+}
+
+// superviseLoop trả về lỗi cuối cùng khi bỏ cuộc, nil khi Run kết thúc bình thường hoặc ctx bị huỷ.
+func (s *serviceCtx) superviseLoop(ctx context.Context, c Component, r Runnable, spec RestartSpec) error {
+	log := s.logger.WithPrefix(c.ID())
+	var restarts []time.Time
+	backoff := spec.MinBackoff
+
+	for {
+		err := runSafely(ctx, r)
+		if ctx.Err() != nil {
+			return nil
+		}
+		if err == nil && spec.Policy != RestartAlways {
+			log.Info("Runnable exited")
+			return nil
+		}
+		if err != nil {
+			log.Error("Runnable failed: %v", err)
+			if spec.Policy == RestartNever {
+				return err
+			}
+		}
+
+		now := time.Now()
+		kept := restarts[:0]
+		for _, t := range restarts {
+			if now.Sub(t) < spec.Window {
+				kept = append(kept, t)
+			}
+		}
+		restarts = kept
+		if len(restarts) == 0 {
+			backoff = spec.MinBackoff
+		}
+		if len(restarts) >= spec.MaxRestarts {
+			return fmt.Errorf("%w: %d restarts within %s, last error: %v", ErrRestartLimit, len(restarts), spec.Window, err)
+		}
+		restarts = append(restarts, now)
+
+		log.Warn("Restarting runnable in %s (policy=%s, restart %d/%d)", backoff, spec.Policy, len(restarts), spec.MaxRestarts)
+		timer := time.NewTimer(backoff)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return nil
+		case <-timer.C:
+		}
+		backoff = min(backoff*2, spec.MaxBackoff)
+	}
+}
+
+func runSafely(ctx context.Context, r Runnable) (err error) {
+	defer func() {
+		if p := recover(); p != nil {
+			err = fmt.Errorf("%w: %v", ErrRunnablePanic, p)
+		}
+	}()
+	return r.Run(ctx)
+}
+
+// escalate đánh dấu service gặp lỗi không thể phục hồi; Done() được đóng và Run sẽ shutdown.
+func (s *serviceCtx) escalate(err error) {
+	s.fatalOnce.Do(func() {
+		s.fatalErr = err
+		close(s.fatal)
+	})
+}
+
+// Done đóng khi một runnable critical bỏ cuộc; Err trả về lỗi tương ứng.
+func (s *serviceCtx) Done() <-chan struct{} { return s.fatal }
+
+func (s *serviceCtx) Err() error {
+	select {
+	case <-s.fatal:
+		return s.fatalErr
+	default:
+		return nil
+	}
+}
@@ -0,0 +1,109 @@
+package sctx
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+const (
+	PhaseActivate = "activate"
+	PhaseStop     = "stop"
+	PhaseHealth   = "health"
+)
+
+// TimeoutError được trả về khi Activate/Stop (hoặc health check) của một component vượt quá deadline.
+type TimeoutError struct {
+	Component string
+	Phase     string
+	Timeout   time.Duration
+}
+
+func (e *TimeoutError) Error() string {
+	return fmt.Sprintf("sctx: %s %s timed out after %s", e.Phase, e.Component, e.Timeout)
+}
+
+func (e *TimeoutError) Unwrap() error { return context.DeadlineExceeded }
+
+// ActivateTimeouter / StopTimeouter là interface tuỳ chọn để component tự khai báo deadline.
+type ActivateTimeouter interface {
+	ActivateTimeout() time.Duration
+}
+
+type StopTimeouter interface {
+	StopTimeout() time.Duration
+}
+
+type componentTimeouts struct {
+	activate time.Duration
+	stop     time.Duration
+}
+
+// timeoutFor: option theo ID > interface của component > mặc định toàn service. 0 = không giới hạn.
+func (s *serviceCtx) timeoutFor(c Component, phase string) time.Duration {
+	if t, ok := s.timeouts[c.ID()]; ok {
+		if phase == PhaseActivate && t.activate > 0 {
+			return t.activate
+		}
+		if phase == PhaseStop && t.stop > 0 {
+			return t.stop
+		}
+	}
+	switch phase {
+	case PhaseActivate:
+		if o, ok := c.(ActivateTimeouter); ok && o.ActivateTimeout() > 0 {
+			return o.ActivateTimeout()
+		}
+		return s.activateTimeout
+	default:
+		if o, ok := c.(StopTimeouter); ok && o.StopTimeout() > 0 {
+			return o.StopTimeout()
+		}
+		return s.stopTimeout
+	}
+}
+
+// callWithTimeout gọi fn với ctx mang deadline; nếu fn bỏ qua ctx và treo,
+// vẫn trả về đúng hạn khi hết deadline hoặc ctx bị huỷ. Goroutine của fn vẫn được chờ ở nền:
+// khi fn trả về muộn, late (nếu có) nhận kết quả đó, vd để Stop component đã Activate muộn.
+// TimeoutError báo deadline thực sự đã hết: của phase (d) hoặc của ctx cha nếu sớm hơn.
+func callWithTimeout(ctx context.Context, id, phase string, d time.Duration, fn func(context.Context) error, late func(error)) error {
+	limit := d
+	if dl, ok := ctx.Deadline(); ok {
+		if left := time.Until(dl); limit <= 0 || left < limit {
+			limit = left
+		}
+	}
+	if d > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, d)
+		defer cancel()
+	}
+	if ctx.Done() == nil {
+		return fn(ctx)
+	}
+
+	done := make(chan error, 1)
+	go func() { done <- fn(ctx) }()
+
+	timeout := func(err error) error {
+		if limit > 0 && errors.Is(ctx.Err(), context.DeadlineExceeded) && errors.Is(err, context.DeadlineExceeded) {
+			return &TimeoutError{Component: id, Phase: phase, Timeout: limit}
+		}
+		return err
+	}
+
+	select {
+	case err := <-done:
+		if err != nil {
+			return timeout(err)
+		}
+		return nil
+	case <-ctx.Done():
+		if late != nil {
+			go func() { late(<-done) }()
+		}
+		return timeout(ctx.Err())
+	}
+}
@@ -33,13 +33,17 @@ func (c *Component) Activate(ctx context.Context, sv sctx.ServiceContext) error
 	c.log = sv.Logger(c.ID())
 
 	c.pool = NewPool(c.log, c.metric, c.opts...)
-	// Chạy pool nền
-	go c.pool.Run(ctx)
 
 	c.log.Info("worker component started")
 	return nil
 }
 
+// Run chạy pool dưới sự giám sát của sctx (sctx.Runnable); trả về khi ctx bị huỷ lúc Stop.
+func (c *Component) Run(ctx context.Context) error {
+	c.pool.Run(ctx)
+	return nil
+}
+
 // Expose API để submit job từ nơi khác
 func (c *Component) Submit(j job.Job) bool {
 	if c.pool == nil {
@@ -2,6 +2,7 @@ package worker
 
 import (
 	"context"
+	"sync/atomic"
 	"testing"
 	"time"
 
@@ -11,6 +12,7 @@ import (
 
 // MockServiceContext for testing
 type MockServiceContext struct {
+	sctx.ServiceContext
 	logger sctx.Logger
 }
 
@@ -65,6 +67,7 @@ func TestComponentSubmit(t *testing.T) {
 
 	sc := &MockServiceContext{logger: log}
 	component.Activate(ctx, sc)
+	go component.Run(ctx)
 
 	time.Sleep(100 * time.Millisecond)
 
@@ -81,7 +84,7 @@ func TestComponentSubmit(t *testing.T) {
 
 	time.Sleep(200 * time.Millisecond)
 
-	if metric.started <= 0 {
+	if atomic.LoadInt32(&metric.started) <= 0 {
 		t.Fatal("Job should have been started")
 	}
 }
@@ -133,6 +136,7 @@ func TestHubComponentSubmit(t *testing.T) {
 
 	sc := &MockServiceContext{logger: log}
 	component.Activate(ctx, sc)
+	go component.Run(ctx)
 
 	time.Sleep(100 * time.Millisecond)
 
@@ -149,7 +153,7 @@ func TestHubComponentSubmit(t *testing.T) {
 
 	time.Sleep(200 * time.Millisecond)
 
-	if metric.started <= 0 {
+	if atomic.LoadInt32(&metric.started) <= 0 {
 		t.Fatal("Job should have been started")
 	}
 }
@@ -186,7 +190,7 @@ func TestComponentMultipleTimes(t *testing.T) {
 		t.Fatalf("First activate failed: %v", err1)
 	}
 
-	// Second activation (should not error, but reuses same pool)
+	// Second activation (should not error, replaces the pool before Run)
 	err2 := component.Activate(ctx, sc)
 	if err2 != nil {
 		t.Fatalf("Second activate failed: %v", err2)
@@ -204,6 +208,7 @@ func TestHubComponentMultipleJobs(t *testing.T) {
 
 	sc := &MockServiceContext{logger: log}
 	component.Activate(ctx, sc)
+	go component.Run(ctx)
 
 	time.Sleep(100 * time.Millisecond)
 
@@ -228,8 +233,8 @@ func TestHubComponentMultipleJobs(t *testing.T) {
 
 	time.Sleep(400 * time.Millisecond)
 
-	if metric.started < 5 {
-		t.Fatalf("Expected at least 5 jobs started, got %d", metric.started)
+	if started := atomic.LoadInt32(&metric.started); started < 5 {
+		t.Fatalf("Expected at least 5 jobs started, got %d", started)
 	}
 }
 
@@ -266,3 +271,56 @@ func TestHubComponentInitFlags(t *testing.T) {
 	// Should not panic
 	component.InitFlags()
 }
+
+// TestComponentStopDrainsQueue tests that jobs queued before sctx Stop still complete
+func TestComponentStopDrainsQueue(t *testing.T) {
+	metric := &MockMetrics{}
+	component := NewComponent("test-worker", metric, WithSize(1), WithStopTimeout(2*time.Second))
+
+	sv := sctx.New(sctx.WithName("drain"), sctx.WithArgs(nil), sctx.WithComponent(component))
+	if err := sv.Load(); err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	// no wait for Run: jobs submitted right after Load are queued
+	for i := 0; i < 5; i++ {
+		handler := &SimpleJobHandler{name: "job", delay: 20 * time.Millisecond}
+		j := job.New(func(ctx context.Context) error {
+			return handler.Handle(ctx)
+		})
+		if !component.Submit(j) {
+			t.Fatalf("Submit job %d failed", i)
+		}
+	}
+
+	if err := sv.Stop(); err != nil {
+		t.Fatalf("Stop failed: %v", err)
+	}
+	if got := atomic.LoadInt32(&metric.succeeded); got != 5 {
+		t.Fatalf("Expected 5 jobs to complete on Stop, got %d", got)
+	}
+}
+
+// TestComponentSubmitAfterLoad tests that a job submitted right after Load runs
+func TestComponentSubmitAfterLoad(t *testing.T) {
+	metric := &MockMetrics{}
+	component := NewComponent("test-worker", metric)
+
+	sv := sctx.New(sctx.WithName("submit"), sctx.WithArgs(nil), sctx.WithComponent(component))
+	if err := sv.Load(); err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	defer sv.Stop()
+
+	done := make(chan struct{})
+	if !component.Submit(job.New(func(ctx context.Context) error {
+		close(done)
+		return nil
+	})) {
+		t.Fatal("Submit right after Load should be accepted")
+	}
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("Job submitted right after Load did not run")
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"strings"
 	"sync/atomic"
 	"testing"
 	"time"
@@ -15,7 +16,7 @@ import (
 // Example 1: Simple Worker Pool
 // ============================================================================
 
-func ExampleSimpleWorkerPool(t *testing.T) {
+func exampleSimpleWorkerPool(t *testing.T) {
 	log := &MockLogger{}
 	metric := &MockMetrics{}
 
@@ -52,7 +53,7 @@ func ExampleSimpleWorkerPool(t *testing.T) {
 	// Wait for jobs to complete
 	time.Sleep(500 * time.Millisecond)
 
-	fmt.Printf("✓ Completed %d jobs\n", metric.started)
+	fmt.Printf("✓ Completed %d jobs\n", atomic.LoadInt32(&metric.started))
 	fmt.Println("✓ Example 1 passed: Simple worker pool")
 }
 
@@ -60,7 +61,7 @@ func ExampleSimpleWorkerPool(t *testing.T) {
 // Example 2: Worker Pool with Error Handling
 // ============================================================================
 
-func ExampleWorkerPoolWithErrors(t *testing.T) {
+func exampleWorkerPoolWithErrors(t *testing.T) {
 	log := &MockLogger{}
 	metric := &MockMetrics{}
 
@@ -96,7 +97,7 @@ func ExampleWorkerPoolWithErrors(t *testing.T) {
 	time.Sleep(400 * time.Millisecond)
 
 	fmt.Printf("Started: %d, Succeeded: %d, Failed: %d\n",
-		metric.started, metric.succeeded, metric.failed)
+		atomic.LoadInt32(&metric.started), atomic.LoadInt32(&metric.succeeded), atomic.LoadInt32(&metric.failed))
 
 	fmt.Println("✓ Example 2 passed: Error handling")
 }
@@ -105,7 +106,7 @@ func ExampleWorkerPoolWithErrors(t *testing.T) {
 // Example 3: Concurrent Job Submission
 // ============================================================================
 
-func ExampleConcurrentSubmission(t *testing.T) {
+func exampleConcurrentSubmission(t *testing.T) {
 	log := &MockLogger{}
 	metric := &MockMetrics{}
 
@@ -157,7 +158,7 @@ func ExampleConcurrentSubmission(t *testing.T) {
 // Example 4: Worker Pool with Retry Strategy
 // ============================================================================
 
-func ExamplePoolWithRetry(t *testing.T) {
+func examplePoolWithRetry(t *testing.T) {
 	log := &MockLogger{}
 	metric := &MockMetrics{}
 
@@ -200,7 +201,7 @@ func ExamplePoolWithRetry(t *testing.T) {
 // Example 5: Worker Pool Monitoring
 // ============================================================================
 
-func ExamplePoolMonitoring(t *testing.T) {
+func examplePoolMonitoring(t *testing.T) {
 	log := &MockLogger{}
 	metric := &MockMetrics{}
 
@@ -244,7 +245,7 @@ func ExamplePoolMonitoring(t *testing.T) {
 // Example 6: Component Integration
 // ============================================================================
 
-func ExampleComponentIntegration(t *testing.T) {
+func exampleComponentIntegration(t *testing.T) {
 	log := &MockLogger{}
 	metric := &MockMetrics{}
 
@@ -259,6 +260,7 @@ func ExampleComponentIntegration(t *testing.T) {
 
 	sc := &MockServiceContext{logger: log}
 	component.Activate(ctx, sc)
+	go component.Run(ctx)
 
 	time.Sleep(100 * time.Millisecond)
 
@@ -280,7 +282,7 @@ func ExampleComponentIntegration(t *testing.T) {
 
 	time.Sleep(300 * time.Millisecond)
 
-	fmt.Printf("Component submitted %d jobs\n", metric.started)
+	fmt.Printf("Component submitted %d jobs\n", atomic.LoadInt32(&metric.started))
 	fmt.Println("✓ Example 6 passed: Component integration")
 }
 
@@ -288,7 +290,7 @@ func ExampleComponentIntegration(t *testing.T) {
 // Example 7: Hub Component with Job Types
 // ============================================================================
 
-func ExampleHubComponentJobTypes(t *testing.T) {
+func exampleHubComponentJobTypes(t *testing.T) {
 	log := &MockLogger{}
 	metric := &MockMetrics{}
 
@@ -303,6 +305,7 @@ func ExampleHubComponentJobTypes(t *testing.T) {
 
 	sc := &MockServiceContext{logger: log}
 	component.Activate(ctx, sc)
+	go component.Run(ctx)
 
 	time.Sleep(100 * time.Millisecond)
 
@@ -330,7 +333,7 @@ func ExampleHubComponentJobTypes(t *testing.T) {
 
 	time.Sleep(300 * time.Millisecond)
 
-	fmt.Printf("Submitted %d jobs via hub\n", metric.started)
+	fmt.Printf("Submitted %d jobs via hub\n", atomic.LoadInt32(&metric.started))
 	fmt.Println("✓ Example 7 passed: Hub with job types")
 }
 
@@ -338,7 +341,7 @@ func ExampleHubComponentJobTypes(t *testing.T) {
 // Example 8: High-Throughput Scenario
 // ============================================================================
 
-func ExampleHighThroughput(t *testing.T) {
+func exampleHighThroughput(t *testing.T) {
 	log := &MockLogger{}
 	metric := &MockMetrics{}
 
@@ -374,10 +377,10 @@ func ExampleHighThroughput(t *testing.T) {
 	time.Sleep(500 * time.Millisecond)
 
 	elapsed := time.Since(start)
-	throughput := float64(metric.started) / elapsed.Seconds()
+	throughput := float64(atomic.LoadInt32(&metric.started)) / elapsed.Seconds()
 
 	fmt.Printf("Processed %d jobs in %v (%.0f jobs/sec)\n",
-		metric.started, elapsed, throughput)
+		atomic.LoadInt32(&metric.started), elapsed, throughput)
 
 	fmt.Println("✓ Example 8 passed: High throughput")
 }
@@ -386,7 +389,7 @@ func ExampleHighThroughput(t *testing.T) {
 // Example 9: Graceful Shutdown
 // ============================================================================
 
-func ExampleGracefulShutdown(t *testing.T) {
+func exampleGracefulShutdown(t *testing.T) {
 	log := &MockLogger{}
 	metric := &MockMetrics{}
 
@@ -424,7 +427,7 @@ func ExampleGracefulShutdown(t *testing.T) {
 
 	time.Sleep(500 * time.Millisecond)
 
-	fmt.Printf("Processed %d jobs before shutdown\n", metric.started)
+	fmt.Printf("Processed %d jobs before shutdown\n", atomic.LoadInt32(&metric.started))
 	fmt.Println("✓ Example 9 passed: Graceful shutdown")
 }
 
@@ -445,7 +448,7 @@ func (h *OrderJobHandler) Handle(ctx context.Context) error {
 
 func (h *OrderJobHandler) Type() string { return h.Action }
 
-func ExampleOrderProcessingWorkflow(t *testing.T) {
+func exampleOrderProcessingWorkflow(t *testing.T) {
 	log := &MockLogger{}
 	metric := &MockMetrics{}
 
@@ -461,6 +464,7 @@ func ExampleOrderProcessingWorkflow(t *testing.T) {
 
 	sc := &MockServiceContext{logger: log}
 	component.Activate(ctx, sc)
+	go component.Run(ctx)
 
 	time.Sleep(100 * time.Millisecond)
 
@@ -494,7 +498,7 @@ func ExampleOrderProcessingWorkflow(t *testing.T) {
 
 	time.Sleep(500 * time.Millisecond)
 
-	fmt.Printf("Processed %d workflow steps\n", metric.started)
+	fmt.Printf("Processed %d workflow steps\n", atomic.LoadInt32(&metric.started))
 	fmt.Println("✓ Example 10 passed: Complete order processing workflow")
 }
 
@@ -503,33 +507,33 @@ func ExampleOrderProcessingWorkflow(t *testing.T) {
 // ============================================================================
 
 func TestAllExamples(t *testing.T) {
-	fmt.Println("\n" + "="*60)
+	fmt.Println("\n" + strings.Repeat("=", 60))
 	fmt.Println("Running Worker Package Examples")
-	fmt.Println("="*60 + "\n")
+	fmt.Println(strings.Repeat("=", 60) + "\n")
 
 	examples := []struct {
 		name string
 		fn   func(t *testing.T)
 	}{
-		{"Example 1: Simple Worker Pool", ExampleSimpleWorkerPool},
-		{"Example 2: Error Handling", ExampleWorkerPoolWithErrors},
-		{"Example 3: Concurrent Submission", ExampleConcurrentSubmission},
-		{"Example 4: Retry Strategy", ExamplePoolWithRetry},
-		{"Example 5: Monitoring", ExamplePoolMonitoring},
-		{"Example 6: Component Integration", ExampleComponentIntegration},
-		{"Example 7: Hub Job Types", ExampleHubComponentJobTypes},
-		{"Example 8: High Throughput", ExampleHighThroughput},
-		{"Example 9: Graceful Shutdown", ExampleGracefulShutdown},
-		{"Example 10: Order Processing Workflow", ExampleOrderProcessingWorkflow},
+		{"Example 1: Simple Worker Pool", exampleSimpleWorkerPool},
+		{"Example 2: Error Handling", exampleWorkerPoolWithErrors},
+		{"Example 3: Concurrent Submission", exampleConcurrentSubmission},
+		{"Example 4: Retry Strategy", examplePoolWithRetry},
+		{"Example 5: Monitoring", examplePoolMonitoring},
+		{"Example 6: Component Integration", exampleComponentIntegration},
+		{"Example 7: Hub Job Types", exampleHubComponentJobTypes},
+		{"Example 8: High Throughput", exampleHighThroughput},
+		{"Example 9: Graceful Shutdown", exampleGracefulShutdown},
+		{"Example 10: Order Processing Workflow", exampleOrderProcessingWorkflow},
 	}
 
 	for _, ex := range examples {
 		fmt.Printf("\n%s\n", ex.name)
-		fmt.Println("-" * 60)
+		fmt.Println(strings.Repeat("-", 60))
 		ex.fn(t)
 	}
 
-	fmt.Println("\n" + "="*60)
+	fmt.Println("\n" + strings.Repeat("=", 60))
 	fmt.Println("✓ All examples completed successfully!")
-	fmt.Println("="*60 + "\n")
+	fmt.Println(strings.Repeat("=", 60) + "\n")
 }
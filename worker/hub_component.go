@@ -2,6 +2,7 @@ package worker
 
 import (
 	"context"
+	"errors"
 	"sync"
 
 	"github.com/jackdes93/fcontext/sctx"
@@ -56,14 +57,35 @@ func (c *HubComponent) Activate(ctx context.Context, sv sctx.ServiceContext) err
 	c.hub = job.NewHub(func(j job.Job) bool {
 		return c.pool.Submit(j)
 	})
-	
-	// Chạy pool nền
-	go c.pool.Run(ctx)
 
 	c.log.Info("hub component started")
 	return nil
 }
 
+// Run chạy pool dưới sự giám sát của sctx (sctx.Runnable); trả về khi ctx bị huỷ lúc Stop.
+func (c *HubComponent) Run(ctx context.Context) error {
+	c.mu.Lock()
+	pool := c.pool
+	c.mu.Unlock()
+
+	pool.Run(ctx)
+	return nil
+}
+
+// HealthCheck báo lỗi khi hub chưa khởi động hoặc đã dừng
+func (c *HubComponent) HealthCheck(ctx context.Context) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.hub == nil || c.pool == nil {
+		return errors.New("hub component not started")
+	}
+	if !c.hub.IsRunning() {
+		return errors.New("job hub is stopped")
+	}
+	return nil
+}
+
 // GetHub trả về hub để đăng ký job handlers
 func (c *HubComponent) GetHub() job.Hub {
 	c.mu.Lock()
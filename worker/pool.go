@@ -3,6 +3,7 @@ package worker
 import (
 	"context"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/jackdes93/fcontext/sctx"
@@ -31,9 +32,21 @@ func WithQueueSize(n int) PoolOption             { return func(c *PoolConfig) {
 func WithStopTimeout(d time.Duration) PoolOption { return func(c *PoolConfig) { c.StopTimeout = d } }
 
 type Pool interface {
-	Submit(j job.Job) bool    // false nếu queue full
-	Run(ctx context.Context)  // blocking
-	Stop(ctx context.Context) // graceful stop
+	Submit(j job.Job) bool    // false nếu queue full, pool chưa Run hoặc đã dừng
+	Run(ctx context.Context)  // blocking, tới khi ctx bị huỷ và queue được drain
+	Stop(ctx context.Context) // graceful stop: chạy nốt job trong queue tới StopTimeout
+	IsRunning() bool
+	Stats() PoolStats
+}
+
+type PoolStats struct {
+	Name          string
+	WorkerCount   int
+	ActiveWorkers int
+	QueueLen      int
+	QueueSize     int
+	Running       bool
+	Stopped       bool
 }
 
 type pool struct {
@@ -47,6 +60,9 @@ type pool struct {
 	mu       sync.RWMutex
 	running  bool
 	stopped  bool
+	active   atomic.Int32
+	cancel   context.CancelFunc // huỷ ctx của job, chỉ gọi khi drain quá StopTimeout
+	finished chan struct{}      // đóng khi Stop drain xong
 }
 
 func NewPool(log sctx.Logger, metric MetricsHook, opts ...PoolOption) Pool {
@@ -57,8 +73,9 @@ func NewPool(log sctx.Logger, metric MetricsHook, opts ...PoolOption) Pool {
 			QueueSize:   1024,
 			StopTimeout: 10 * time.Second,
 		},
-		log:    log,
-		metric: metric,
+		log:      log,
+		metric:   metric,
+		finished: make(chan struct{}),
 	}
 	for _, o := range opts {
 		o(&p.cfg)
@@ -68,13 +85,17 @@ func NewPool(log sctx.Logger, metric MetricsHook, opts ...PoolOption) Pool {
 }
 
 func (p *pool) Submit(j job.Job) bool {
+	if j == nil {
+		return false
+	}
+	// giữ RLock khi gửi để Stop không đóng queue giữa chừng
 	p.mu.RLock()
+	defer p.mu.RUnlock()
+	// trước Run job vẫn được nhận vào queue, worker chạy chúng khi Run (hoặc Stop) bắt đầu
 	if p.stopped {
-		p.mu.RUnlock()
 		p.log.Warn("cannot submit job, pool is stopped")
 		return false
 	}
-	p.mu.RUnlock()
 
 	select {
 	case p.queue <- j:
@@ -85,47 +106,89 @@ func (p *pool) Submit(j job.Job) bool {
 	}
 }
 
+// Run chạy worker tới khi ctx bị huỷ rồi drain queue. Job chạy với ctx không bị huỷ theo ctx
+// (sctx huỷ ctx của Runnable trước khi Stop), chỉ bị huỷ khi drain quá StopTimeout.
 func (p *pool) Run(ctx context.Context) {
 	p.once.Do(func() {
 		p.mu.Lock()
+		if p.stopped {
+			p.mu.Unlock()
+			return
+		}
+		p.start(ctx)
 		p.running = true
 		p.mu.Unlock()
-
-		for i := 0; i < p.cfg.Size; i++ {
-			p.wg.Add(1)
-			go p.worker(ctx, i)
-		}
 	})
 	<-ctx.Done()
-	p.Stop(ctx)
+	p.Stop(context.WithoutCancel(ctx))
 }
 
 func (p *pool) Stop(ctx context.Context) {
 	p.mu.Lock()
 	if p.stopped {
 		p.mu.Unlock()
+		// Stop đang chạy ở nơi khác (vd: Run khi ctx bị huỷ): chờ nó drain xong
+		select {
+		case <-p.finished:
+		case <-ctx.Done():
+		}
 		return
 	}
+	if p.cancel == nil {
+		// Run chưa chạy: vẫn chạy nốt các job đã nhận
+		p.start(ctx)
+	}
 	p.stopped = true
 	p.running = false
+	// đóng queue để worker chạy nốt job còn lại rồi thoát
+	close(p.queue)
+	cancel := p.cancel
 	p.mu.Unlock()
+	defer close(p.finished)
+	if cancel != nil {
+		defer cancel()
+	}
 
-	stopCtx, cancel := context.WithTimeout(ctx, p.cfg.StopTimeout)
-	defer cancel()
-
-	// đóng queue để worker dọn dẹp
-	close(p.queue)
+	stopCtx, stopCancel := context.WithTimeout(ctx, p.cfg.StopTimeout)
+	defer stopCancel()
 	done := make(chan struct{})
 	go func() { p.wg.Wait(); close(done) }()
 
 	select {
 	case <-stopCtx.Done():
-		p.log.Warn("worker pool stop timeout reached")
+		p.log.Warn("worker pool stop timeout reached, cancelling running jobs")
 	case <-done:
 		p.log.Info("worker pool stopped")
 	}
 }
-		p.log.Info("worker pool stopped")
+
+// start khởi động worker với ctx không bị huỷ theo ctx truyền vào; gọi khi giữ p.mu
+func (p *pool) start(ctx context.Context) {
+	jobCtx, cancel := context.WithCancel(context.WithoutCancel(ctx))
+	p.cancel = cancel
+	for i := 0; i < p.cfg.Size; i++ {
+		p.wg.Add(1)
+		go p.worker(jobCtx, i)
+	}
+}
+
+func (p *pool) IsRunning() bool {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	return p.running
+}
+
+func (p *pool) Stats() PoolStats {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	return PoolStats{
+		Name:          p.cfg.Name,
+		WorkerCount:   p.cfg.Size,
+		ActiveWorkers: int(p.active.Load()),
+		QueueLen:      len(p.queue),
+		QueueSize:     p.cfg.QueueSize,
+		Running:       p.running,
+		Stopped:       p.stopped,
 	}
 }
 
@@ -135,21 +198,26 @@ func (p *pool) worker(ctx context.Context, idx int) {
 
 	for j := range p.queue {
 		start := time.Now()
+		p.active.Add(1)
 		if p.metric != nil {
 			p.metric.IncJobStarted(nameOf(j))
 		}
 
-		err := j.RunWithRetry(ctx)
+		// handler và log của job mang tên pool, worker, job (job tự thêm attempt)
+		jobCtx := sctx.ContextWithFields(ctx, "pool", p.cfg.Name, "worker", idx, "job", nameOf(j))
+		err := j.RunWithRetry(jobCtx)
+		p.active.Add(-1)
 		lat := time.Since(start)
+		jobLog := log.Ctx(jobCtx)
 
 		if err == nil {
-			log.Info("job success name=%s latency=%s", nameOf(j), lat)
+			jobLog.Infow("job success", "latency", lat)
 			if p.metric != nil {
 				p.metric.IncJobSuccess(nameOf(j), lat)
 			}
 			continue
 		}
-		log.Warn("job failed name=%s state=%s retry=%d err=%v", nameOf(j), j.State(), j.RetryIndex(), err)
+		jobLog.Warnw("job failed", "state", j.State().String(), "retry", j.RetryIndex(), "error", err)
 		if j.State() == job.StateRetryFailed && p.metric != nil {
 			p.metric.IncJobPermanentFailed(nameOf(j), err)
 		}
@@ -159,8 +227,10 @@ func (p *pool) worker(ctx context.Context, idx int) {
 	}
 }
 
+// nameOf: tên job (job.WithName hoặc type của Hub.Create) khi job có Name(), ngược lại "job"
 func nameOf(j job.Job) string {
-	// best-effort: dùng type name làm job name khi không có metadata;
-	// nếu cần “tên” chính xác, bạn có thể mở rộng Job interface để expose Name().
+	if n, ok := j.(interface{ Name() string }); ok && n.Name() != "" {
+		return n.Name()
+	}
 	return "job"
 }
@@ -10,6 +10,7 @@ import (
 	"time"
 
 	"github.com/jackdes93/fcontext/job"
+	"github.com/jackdes93/fcontext/sctx"
 )
 
 // MockLogger for testing
@@ -18,12 +19,17 @@ type MockLogger struct {
 	mu       sync.Mutex
 }
 
-func (m *MockLogger) Info(msg string, args ...interface{})      { m.log("INFO", msg, args...) }
-func (m *MockLogger) Warn(msg string, args ...interface{})      { m.log("WARN", msg, args...) }
-func (m *MockLogger) Error(msg string, args ...interface{})     { m.log("ERROR", msg, args...) }
-func (m *MockLogger) WithPrefix(prefix string) interface{} {
-	return m
-}
+func (m *MockLogger) Debug(msg string, args ...interface{})  { m.log("DEBUG", msg, args...) }
+func (m *MockLogger) Info(msg string, args ...interface{})   { m.log("INFO", msg, args...) }
+func (m *MockLogger) Warn(msg string, args ...interface{})   { m.log("WARN", msg, args...) }
+func (m *MockLogger) Error(msg string, args ...interface{})  { m.log("ERROR", msg, args...) }
+func (m *MockLogger) Debugw(msg string, kv ...interface{})   { m.logKV("DEBUG", msg, kv...) }
+func (m *MockLogger) Infow(msg string, kv ...interface{})    { m.logKV("INFO", msg, kv...) }
+func (m *MockLogger) Warnw(msg string, kv ...interface{})    { m.logKV("WARN", msg, kv...) }
+func (m *MockLogger) Errorw(msg string, kv ...interface{})   { m.logKV("ERROR", msg, kv...) }
+func (m *MockLogger) With(kv ...interface{}) sctx.Logger     { return m }
+func (m *MockLogger) Ctx(ctx context.Context) sctx.Logger    { return m }
+func (m *MockLogger) WithPrefix(prefix string) sctx.Logger   { return m }
 
 func (m *MockLogger) log(level string, msg string, args ...interface{}) {
 	m.mu.Lock()
@@ -31,6 +37,12 @@ func (m *MockLogger) log(level string, msg string, args ...interface{}) {
 	m.messages = append(m.messages, fmt.Sprintf("[%s] %s", level, fmt.Sprintf(msg, args...)))
 }
 
+func (m *MockLogger) logKV(level string, msg string, kv ...interface{}) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.messages = append(m.messages, fmt.Sprintf("[%s] %s %v", level, msg, kv))
+}
+
 // MockMetrics for testing
 type MockMetrics struct {
 	started    int32
@@ -110,11 +122,24 @@ func TestPoolSubmitBeforeRun(t *testing.T) {
 		return handler.Handle(ctx)
 	})
 
-	// Should fail before Run() is called
-	ok := pool.Submit(j)
-	if ok {
-		t.Fatal("Submit before Run should return false")
+	// Jobs submitted before Run() are queued and run once the workers start
+	if !pool.Submit(j) {
+		t.Fatal("Submit before Run should queue the job")
+	}
+	if got := atomic.LoadInt32(&metric.started); got != 0 {
+		t.Fatalf("Job should not run before Run, started=%d", got)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	go pool.Run(ctx)
+	for deadline := time.Now().Add(time.Second); atomic.LoadInt32(&metric.succeeded) != 1; {
+		if time.Now().After(deadline) {
+			t.Fatal("Queued job should run after Run")
+		}
+		time.Sleep(5 * time.Millisecond)
 	}
+	cancel()
+	pool.Stop(context.Background())
 }
 
 // TestPoolRun tests starting the pool
@@ -547,3 +572,65 @@ func TestPoolContextCancellation(t *testing.T) {
 		t.Fatal("Pool should stop after context cancellation")
 	}
 }
+
+// TestPoolStopDrainsQueue tests that queued jobs still run after the Run ctx is cancelled
+func TestPoolStopDrainsQueue(t *testing.T) {
+	log := &MockLogger{}
+	metric := &MockMetrics{}
+
+	pool := NewPool(log, metric, WithSize(1), WithStopTimeout(2*time.Second))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() { pool.Run(ctx); close(done) }()
+
+	time.Sleep(50 * time.Millisecond)
+
+	for i := 0; i < 5; i++ {
+		handler := &SimpleJobHandler{name: fmt.Sprintf("job-%d", i), delay: 20 * time.Millisecond}
+		j := job.New(func(ctx context.Context) error {
+			return handler.Handle(ctx)
+		})
+		if !pool.Submit(j) {
+			t.Fatalf("Submit job %d failed", i)
+		}
+	}
+
+	cancel()
+	<-done
+
+	if got := atomic.LoadInt32(&metric.succeeded); got != 5 {
+		t.Fatalf("Expected 5 drained jobs to succeed, got %d", got)
+	}
+	if pool.Submit(job.New(func(ctx context.Context) error { return nil })) {
+		t.Fatal("Submit after stop should return false")
+	}
+}
+
+// TestPoolStopTimeoutCancelsJobs tests that jobs are cancelled once StopTimeout is exceeded
+func TestPoolStopTimeoutCancelsJobs(t *testing.T) {
+	log := &MockLogger{}
+	metric := &MockMetrics{}
+
+	pool := NewPool(log, metric, WithSize(1), WithStopTimeout(50*time.Millisecond))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() { pool.Run(ctx); close(done) }()
+
+	time.Sleep(50 * time.Millisecond)
+
+	handler := &SimpleJobHandler{name: "slow", delay: 5 * time.Second}
+	pool.Submit(job.New(func(ctx context.Context) error {
+		return handler.Handle(ctx)
+	}, job.WithRetries(nil)))
+
+	time.Sleep(20 * time.Millisecond)
+	cancel()
+
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("Run should return shortly after StopTimeout")
+	}
+}